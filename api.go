@@ -0,0 +1,210 @@
+package gooseglass
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pressly/goose/v3"
+
+	"github.com/crhntr/gooseglass/internal/gooseerr"
+)
+
+// The JSON API lets tools such as the client package drive the server. Run endpoints take the
+// same override_reason form value as the pages and go through approvals and maintenance windows.
+const (
+	apiStatusPath   = "/api/status"
+	apiUpPath       = "/api/up"
+	apiUpByOnePath  = "/api/up-by-one"
+	apiUpToPath     = "/api/up-to/{version}"
+	apiDownPath     = "/api/down"
+	apiDownToPath   = "/api/down-to/{version}"
+	apiApplyPath    = "/api/apply/{version}"
+	apiApprovalPath = "/api/approvals/{id}"
+)
+
+func (s *server) apiRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET "+apiStatusPath, s.apiStatus)
+	mux.HandleFunc("POST "+apiUpPath, s.apiRun(operationUp))
+	mux.HandleFunc("POST "+apiUpByOnePath, s.apiRun(operationUpByOne))
+	mux.HandleFunc("POST "+apiUpToPath, s.apiRun(operationUpTo))
+	mux.HandleFunc("POST "+apiDownPath, s.apiRun(operationDown))
+	mux.HandleFunc("POST "+apiDownToPath, s.apiRun(operationDownTo))
+	mux.HandleFunc("POST "+apiApplyPath, s.apiRun(operationApply))
+	mux.HandleFunc("GET "+apiApprovalPath, s.apiApproval)
+}
+
+// apiApproval reports an approval request so clients can wait for the run it holds.
+func (s *server) apiApproval(response http.ResponseWriter, request *http.Request) {
+	if s.approvals == nil {
+		writeJSONError(response, errApprovalsNotConfigured)
+		return
+	}
+	approval, err := s.approvals.get(request.PathValue("id"), s.now())
+	if err != nil {
+		writeJSONError(response, err)
+		return
+	}
+	writeJSON(response, http.StatusOK, approval)
+}
+
+// StatusReport is the JSON body of GET /api/status. Other gooseglass servers read it to compare
+// environments.
+type StatusReport struct {
+	Environment string              `json:"environment,omitempty"`
+	Production  bool                `json:"production,omitempty"`
+	DBVersion   int64               `json:"db_version"`
+	Migrations  []ReportedMigration `json:"migrations"`
+}
+
+// ReportedMigration is one migration in a StatusReport. State is one of applied, pending, missing
+// or untracked.
+type ReportedMigration struct {
+	Version   int64      `json:"version"`
+	Type      string     `json:"type,omitempty"`
+	Path      string     `json:"path,omitempty"`
+	State     string     `json:"state"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+func newStatusReport(table statusTable, env *Environment) StatusReport {
+	report := StatusReport{DBVersion: table.DBVersion, Migrations: []ReportedMigration{}}
+	if env != nil {
+		report.Environment, report.Production = env.Name, env.Production
+	}
+	for _, row := range table.Migrations {
+		if row.Source == nil {
+			continue
+		}
+		m := ReportedMigration{Version: row.Source.Version, Type: string(row.Source.Type), Path: row.Source.Path, State: row.Kind}
+		if !row.AppliedAt.IsZero() {
+			m.AppliedAt = &row.AppliedAt
+		}
+		report.Migrations = append(report.Migrations, m)
+	}
+	return report
+}
+
+func (s *server) apiStatus(response http.ResponseWriter, request *http.Request) {
+	list, err := s.provider.Status(request.Context())
+	if err != nil {
+		writeJSONError(response, err)
+		return
+	}
+	writeJSON(response, http.StatusOK, newStatusReport(newStatusTable(list, statusQuery{}, s.allowMissing, 0), s.environment))
+}
+
+// RunReport is the JSON body of the run endpoints. Approval is set instead of Run when the run
+// waits for approval. When a migration fails, Results holds what was applied before it, Failed
+// the failed migration and Error the reason.
+type RunReport struct {
+	Run        *Run             `json:"run,omitempty"`
+	Results    []ReportedResult `json:"results"`
+	Failed     *ReportedResult  `json:"failed,omitempty"`
+	Approval   *ApprovalRequest `json:"approval,omitempty"`
+	Error      string           `json:"error,omitempty"`
+	GooseError string           `json:"goose_error,omitempty"`
+}
+
+// ReportedResult is one goose.MigrationResult in a RunReport.
+type ReportedResult struct {
+	Version   int64         `json:"version"`
+	Type      string        `json:"type,omitempty"`
+	Path      string        `json:"path,omitempty"`
+	Direction string        `json:"direction"`
+	Duration  time.Duration `json:"duration"`
+	Empty     bool          `json:"empty,omitempty"`
+	Error     string        `json:"error,omitempty"`
+}
+
+func newReportedResults(results []*goose.MigrationResult) []ReportedResult {
+	list := make([]ReportedResult, 0, len(results))
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		list = append(list, newReportedResult(result))
+	}
+	return list
+}
+
+func newReportedResult(result *goose.MigrationResult) ReportedResult {
+	r := ReportedResult{Direction: result.Direction, Duration: result.Duration, Empty: result.Empty}
+	if src := result.Source; src != nil {
+		r.Version, r.Type, r.Path = src.Version, string(src.Type), src.Path
+	}
+	if result.Error != nil {
+		r.Error = result.Error.Error()
+	}
+	return r
+}
+
+// apiRun runs the operation for a JSON API request.
+func (s *server) apiRun(operation string) http.HandlerFunc {
+	return func(response http.ResponseWriter, request *http.Request) {
+		plan := Plan{Operation: operation}
+		if v := request.PathValue("version"); v != "" {
+			version, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				writeJSONError(response, statusError{code: http.StatusBadRequest, err: fmt.Errorf("invalid version %q", v)})
+				return
+			}
+			plan.Version = version
+		}
+		if operation == operationApply {
+			if !s.allowMissing {
+				writeJSONError(response, statusError{code: http.StatusForbidden, err: fmt.Errorf("applying missing migration %d is not allowed", plan.Version)})
+				return
+			}
+			if request.FormValue("direction") == "down" {
+				plan.Operation = operationApplyDown
+			}
+		}
+		if plan.rollsBack() {
+			plan.Reason = request.FormValue("override_reason")
+		}
+		result, err := s.migrate(request.Context(), request, plan)
+		report := RunReport{Results: newReportedResults(result.Results), Approval: result.Approval}
+		if result.Run.ID != "" {
+			report.Run = &result.Run
+		}
+		var partial *goose.PartialError
+		switch {
+		case errors.As(err, &partial):
+			report.Results = newReportedResults(partial.Applied)
+			if partial.Failed != nil {
+				failed := newReportedResult(partial.Failed)
+				report.Failed = &failed
+			}
+			report.Error = partial.Err.Error()
+			writeJSON(response, http.StatusInternalServerError, report)
+		case err != nil:
+			writeJSONError(response, err)
+		case report.Approval != nil:
+			writeJSON(response, http.StatusAccepted, report)
+		default:
+			writeJSON(response, http.StatusOK, report)
+		}
+	}
+}
+
+func writeJSON(response http.ResponseWriter, code int, v any) {
+	response.Header().Set("Content-Type", "application/json")
+	response.WriteHeader(code)
+	_ = json.NewEncoder(response).Encode(v)
+}
+
+// writeJSONError responds with a RunReport holding just the error and the status code of the
+// error, or 500.
+func writeJSONError(response http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	var sc interface{ StatusCode() int }
+	if errors.As(err, &sc) {
+		code = sc.StatusCode()
+	}
+	report := RunReport{Error: err.Error(), GooseError: gooseerr.Name(err)}
+	writeJSON(response, code, report)
+}
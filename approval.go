@@ -0,0 +1,268 @@
+package gooseglass
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	approvalStatePending  = "pending"
+	approvalStateApproved = "approved"
+	approvalStateRejected = "rejected"
+	approvalStateExpired  = "expired"
+)
+
+// ApprovalRequest is a run waiting for a second person to approve it. Only pending requests can
+// be approved or rejected, and only by someone other than the requester; the requester may reject
+// (withdraw) their own request.
+type ApprovalRequest struct {
+	ID          string
+	Plan        Plan
+	State       string
+	RequestedAt time.Time
+	ExpiresAt   time.Time
+	DecidedBy   string
+	DecidedAt   time.Time
+	RunID       string
+	Error       string
+}
+
+func (request ApprovalRequest) IsPending() bool { return request.State == approvalStatePending }
+
+// IsDone reports whether nothing more will happen to the request: it was rejected or expired, or
+// it was approved and its run finished.
+func (request ApprovalRequest) IsDone() bool {
+	switch request.State {
+	case approvalStateApproved:
+		return request.RunID != "" || request.Error != ""
+	case approvalStatePending:
+		return false
+	default:
+		return true
+	}
+}
+
+// ApprovalNotifier tells approvers about new requests and requesters about decisions.
+type ApprovalNotifier interface {
+	NotifyApproval(ctx context.Context, request ApprovalRequest) error
+}
+
+// approvalRetention is how long requests are kept after they are done.
+const approvalRetention = 7 * 24 * time.Hour
+
+// Approvals holds runs until a second person approves them. Requests are kept in memory until a
+// week after they are done.
+type Approvals struct {
+	ttl           time.Duration
+	notifier      ApprovalNotifier
+	notifications notifications
+
+	mu       sync.Mutex
+	requests []ApprovalRequest
+}
+
+// NewApprovals expires requests not decided within ttl, a day when zero. The notifier may be nil;
+// it is called from a queue so a slow notifier does not hold up runs.
+func NewApprovals(ttl time.Duration, notifier ApprovalNotifier) *Approvals {
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+	return &Approvals{ttl: ttl, notifier: notifier}
+}
+
+// WithApprovals makes every run wait for a second person to approve it on the approvals page,
+// including scheduled runs and backup restores. Pages panics unless WithActor is also given, because
+// the default actor can not tell people apart reliably.
+func WithApprovals(approvals *Approvals) Option {
+	return func(s *server) { s.approvals = approvals }
+}
+
+func (approvals *Approvals) request(ctx context.Context, plan Plan, now time.Time) ApprovalRequest {
+	request := ApprovalRequest{
+		ID:          rand.Text(),
+		Plan:        plan,
+		State:       approvalStatePending,
+		RequestedAt: now,
+		ExpiresAt:   now.Add(approvals.ttl),
+	}
+	approvals.mu.Lock()
+	approvals.requests = append(approvals.requests, request)
+	approvals.mu.Unlock()
+	approvals.notify(ctx, request)
+	return request
+}
+
+// list returns the requests newest first after expiring stale ones.
+func (approvals *Approvals) list(now time.Time) []ApprovalRequest {
+	approvals.mu.Lock()
+	defer approvals.mu.Unlock()
+	approvals.expire(now)
+	list := slices.Clone(approvals.requests)
+	slices.Reverse(list)
+	return list
+}
+
+// expire marks stale requests expired and drops requests done for longer than the retention. The
+// caller holds mu.
+func (approvals *Approvals) expire(now time.Time) {
+	for i := range approvals.requests {
+		if r := &approvals.requests[i]; r.IsPending() && !now.Before(r.ExpiresAt) {
+			r.State = approvalStateExpired
+		}
+	}
+	approvals.requests = slices.DeleteFunc(approvals.requests, func(r ApprovalRequest) bool {
+		done := r.DecidedAt
+		if r.State == approvalStateExpired {
+			done = r.ExpiresAt
+		}
+		return r.IsDone() && now.Sub(done) > approvalRetention
+	})
+}
+
+func (approvals *Approvals) get(id string, now time.Time) (ApprovalRequest, error) {
+	approvals.mu.Lock()
+	defer approvals.mu.Unlock()
+	approvals.expire(now)
+	i := slices.IndexFunc(approvals.requests, func(r ApprovalRequest) bool { return r.ID == id })
+	if i < 0 {
+		return ApprovalRequest{}, statusError{code: http.StatusNotFound, err: fmt.Errorf("approval request %q not found", id)}
+	}
+	return approvals.requests[i], nil
+}
+
+// check returns the request actor wants to decide, or why they can not.
+func (approvals *Approvals) check(id, actor string, approve bool, now time.Time) (ApprovalRequest, error) {
+	approvals.mu.Lock()
+	defer approvals.mu.Unlock()
+	request, err := approvals.decidable(id, actor, approve, now)
+	if err != nil {
+		return ApprovalRequest{}, err
+	}
+	return *request, nil
+}
+
+// decide moves a pending request to approved or rejected.
+func (approvals *Approvals) decide(ctx context.Context, id, actor string, approve bool, now time.Time) (ApprovalRequest, error) {
+	approvals.mu.Lock()
+	request, err := approvals.decidable(id, actor, approve, now)
+	if err != nil {
+		approvals.mu.Unlock()
+		return ApprovalRequest{}, err
+	}
+	request.State = approvalStateRejected
+	if approve {
+		request.State = approvalStateApproved
+	}
+	request.DecidedBy, request.DecidedAt = actor, now
+	decided := *request
+	approvals.mu.Unlock()
+	approvals.notify(ctx, decided)
+	return decided, nil
+}
+
+// decidable returns the pending request with id if actor may decide it. The caller holds mu.
+func (approvals *Approvals) decidable(id, actor string, approve bool, now time.Time) (*ApprovalRequest, error) {
+	approvals.expire(now)
+	i := slices.IndexFunc(approvals.requests, func(r ApprovalRequest) bool { return r.ID == id })
+	if i < 0 {
+		return nil, statusError{code: http.StatusNotFound, err: fmt.Errorf("approval request %q not found", id)}
+	}
+	request := &approvals.requests[i]
+	switch {
+	case request.State == approvalStateExpired:
+		return nil, statusError{code: http.StatusGone, err: fmt.Errorf("approval request for %s expired at %s", request.Plan, request.ExpiresAt.Format(time.RFC3339))}
+	case !request.IsPending():
+		return nil, statusError{code: http.StatusConflict, err: fmt.Errorf("approval request for %s was already %s by %s", request.Plan, request.State, request.DecidedBy)}
+	case approve && actor == request.Plan.Actor:
+		return nil, statusError{code: http.StatusForbidden, err: fmt.Errorf("%s requested %s and can not also approve it", actor, request.Plan)}
+	}
+	return request, nil
+}
+
+// finish records the run an approved request started and returns the finished request.
+func (approvals *Approvals) finish(id string, result runResult, err error) ApprovalRequest {
+	approvals.mu.Lock()
+	defer approvals.mu.Unlock()
+	i := slices.IndexFunc(approvals.requests, func(r ApprovalRequest) bool { return r.ID == id })
+	if i < 0 {
+		return ApprovalRequest{}
+	}
+	approvals.requests[i].RunID = result.Run.ID
+	if err != nil {
+		approvals.requests[i].Error = err.Error()
+	}
+	return approvals.requests[i]
+}
+
+// notify queues the request for the notifier.
+func (approvals *Approvals) notify(ctx context.Context, request ApprovalRequest) {
+	if approvals.notifier == nil {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	approvals.notifications.enqueue(func() {
+		if err := approvals.notifier.NotifyApproval(ctx, request); err != nil {
+			slog.ErrorContext(ctx, "failed to send approval notification", slog.String("approval", request.ID), slog.String("error", err.Error()))
+		}
+	})
+}
+
+type approvalsPage struct {
+	Requests []ApprovalRequest
+	Viewer   string
+}
+
+func (s *server) Approvals(_ context.Context, request *http.Request) (approvalsPage, error) {
+	if s.approvals == nil {
+		return approvalsPage{}, errApprovalsNotConfigured
+	}
+	return approvalsPage{Requests: s.approvals.list(s.now()), Viewer: s.actor(request)}, nil
+}
+
+// Approve runs an approved request. The request stays pending, so it can be approved again, until
+// the run holds the run lock and passes the maintenance windows.
+func (s *server) Approve(ctx context.Context, request *http.Request, id string) (runResult, error) {
+	if s.approvals == nil {
+		return runResult{}, errApprovalsNotConfigured
+	}
+	actor := s.actor(request)
+	approval, err := s.approvals.check(id, actor, true, s.now())
+	if err != nil {
+		return runResult{}, err
+	}
+	plan := approval.Plan
+	plan.Approver = actor
+	var approved bool
+	result, err := s.execute(ctx, plan, func() error {
+		_, err := s.approvals.decide(ctx, id, actor, true, s.now())
+		approved = err == nil
+		return err
+	})
+	if approved {
+		s.settleSchedule(ctx, s.approvals.finish(id, result, err), result.Run.Entries)
+	}
+	return result, err
+}
+
+func (s *server) Reject(ctx context.Context, request *http.Request, id string) (approvalsPage, error) {
+	if s.approvals == nil {
+		return approvalsPage{}, errApprovalsNotConfigured
+	}
+	rejected, err := s.approvals.decide(ctx, id, s.actor(request), false, s.now())
+	if err != nil {
+		return approvalsPage{}, err
+	}
+	s.settleSchedule(ctx, rejected, nil)
+	return s.Approvals(ctx, request)
+}
+
+var errApprovalsWithoutActor = errors.New("gooseglass: WithApprovals requires WithActor")
+
+var errApprovalsNotConfigured = statusError{code: http.StatusNotFound, err: errors.New("approvals are not configured")}
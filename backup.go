@@ -0,0 +1,213 @@
+package gooseglass
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Backups takes a backup before every Down and DownTo and lists and restores them on the backups
+// page.
+type Backups interface {
+	Backup(ctx context.Context) (Backup, error)
+	List(ctx context.Context) ([]Backup, error)
+	Restore(ctx context.Context, name string) error
+}
+
+type Backup struct {
+	Name string
+	Size int64
+	Time time.Time
+}
+
+// WithBackups takes a backup before every Down and DownTo and adds a backups page to restore
+// them from.
+func WithBackups(backups Backups) Option {
+	return func(s *server) { s.backups = backups }
+}
+
+const (
+	sqliteBackupPrefix = "backup-"
+	sqliteBackupSuffix = ".sqlite"
+	sqliteBackupLayout = "20060102T150405.000000000Z"
+)
+
+// SQLiteBackups writes backups of a SQLite database with VACUUM INTO to files in a directory. With
+// libSQL the directory is on the database server and Restore only works when it is shared with
+// this process.
+type SQLiteBackups struct {
+	db  *sql.DB
+	dir string
+}
+
+func NewSQLiteBackups(db *sql.DB, dir string) *SQLiteBackups {
+	return &SQLiteBackups{db: db, dir: dir}
+}
+
+func (b *SQLiteBackups) Backup(ctx context.Context) (Backup, error) {
+	if err := os.MkdirAll(b.dir, 0o755); err != nil {
+		return Backup{}, err
+	}
+	now := time.Now().UTC()
+	name := sqliteBackupPrefix + now.Format(sqliteBackupLayout) + sqliteBackupSuffix
+	if _, err := b.db.ExecContext(ctx, `VACUUM INTO ?`, filepath.Join(b.dir, name)); err != nil {
+		return Backup{}, err
+	}
+	backup := Backup{Name: name, Time: now}
+	if info, err := os.Stat(filepath.Join(b.dir, name)); err == nil {
+		backup.Size = info.Size()
+	}
+	return backup, nil
+}
+
+// List returns the backups newest first.
+func (b *SQLiteBackups) List(context.Context) ([]Backup, error) {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var list []Backup
+	for _, entry := range entries {
+		t, ok := parseSQLiteBackupName(entry.Name())
+		if !ok || entry.IsDir() {
+			continue
+		}
+		backup := Backup{Name: entry.Name(), Time: t}
+		if info, err := entry.Info(); err == nil {
+			backup.Size = info.Size()
+		}
+		list = append(list, backup)
+	}
+	slices.SortFunc(list, func(a, b Backup) int { return b.Time.Compare(a.Time) })
+	return list, nil
+}
+
+// Restore replaces every table, index, view and trigger in the database with those in the named
+// backup in a single transaction.
+func (b *SQLiteBackups) Restore(ctx context.Context, name string) (err error) {
+	if _, ok := parseSQLiteBackupName(name); !ok || filepath.Base(name) != name {
+		return fmt.Errorf("%q is not a backup", name)
+	}
+	path := filepath.Join(b.dir, name)
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+	conn, err := b.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, conn.Close()) }()
+	// foreign_keys can not be changed inside a transaction.
+	if _, err := conn.ExecContext(ctx, `PRAGMA foreign_keys = OFF`); err != nil {
+		return err
+	}
+	defer func() {
+		_, fkErr := conn.ExecContext(context.WithoutCancel(ctx), `PRAGMA foreign_keys = ON`)
+		err = errors.Join(err, fkErr)
+	}()
+	if _, err := conn.ExecContext(ctx, `ATTACH DATABASE ? AS backup`, path); err != nil {
+		return err
+	}
+	defer func() {
+		_, detachErr := conn.ExecContext(context.WithoutCancel(ctx), `DETACH DATABASE backup`)
+		err = errors.Join(err, detachErr)
+	}()
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := restoreSQLite(ctx, tx); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	return tx.Commit()
+}
+
+func restoreSQLite(ctx context.Context, tx *sql.Tx) error {
+	type object struct{ kind, name, sql string }
+	objects := func(schema string) ([]object, error) {
+		rows, err := tx.QueryContext(ctx, `SELECT type, name, coalesce(sql, '') FROM `+schema+`.sqlite_master
+			WHERE name NOT LIKE 'sqlite_%' ORDER BY CASE type WHEN 'table' THEN 0 WHEN 'index' THEN 1 ELSE 2 END, name`)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = rows.Close() }()
+		var list []object
+		for rows.Next() {
+			var o object
+			if err := rows.Scan(&o.kind, &o.name, &o.sql); err != nil {
+				return nil, err
+			}
+			list = append(list, o)
+		}
+		return list, rows.Err()
+	}
+	current, err := objects("main")
+	if err != nil {
+		return err
+	}
+	for _, o := range slices.Backward(current) {
+		if o.kind == "index" {
+			// Dropped with their tables; automatic indexes can not be dropped at all.
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `DROP `+strings.ToUpper(o.kind)+` IF EXISTS main.`+quoteIdentifier(o.name)); err != nil {
+			return err
+		}
+	}
+	saved, err := objects("backup")
+	if err != nil {
+		return err
+	}
+	for _, o := range saved {
+		if o.sql == "" {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, o.sql); err != nil {
+			return err
+		}
+		if o.kind == "table" {
+			if _, err := tx.ExecContext(ctx, `INSERT INTO main.`+quoteIdentifier(o.name)+` SELECT * FROM backup.`+quoteIdentifier(o.name)); err != nil {
+				return err
+			}
+		}
+	}
+	var hasSequence bool
+	if err := tx.QueryRowContext(ctx, `SELECT count(*) > 0 FROM backup.sqlite_master WHERE name = 'sqlite_sequence'`).Scan(&hasSequence); err != nil {
+		return err
+	}
+	if hasSequence {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM main.sqlite_sequence`); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO main.sqlite_sequence SELECT * FROM backup.sqlite_sequence`); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseSQLiteBackupName(name string) (time.Time, bool) {
+	s, ok := strings.CutPrefix(name, sqliteBackupPrefix)
+	if !ok {
+		return time.Time{}, false
+	}
+	s, ok = strings.CutSuffix(s, sqliteBackupSuffix)
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(sqliteBackupLayout, s)
+	return t, err == nil
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
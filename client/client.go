@@ -0,0 +1,302 @@
+// Package client drives a remote gooseglass server through its JSON API.
+//
+// A Client implements gooseglass.Provider, so a remote server can be used anywhere a local
+// provider can, including as a gooseglass.LocalPeer or behind another set of pages.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pressly/goose/v3"
+
+	"github.com/crhntr/gooseglass"
+)
+
+var (
+	_ gooseglass.Provider       = (*Client)(nil)
+	_ gooseglass.UpByOner       = (*Client)(nil)
+	_ gooseglass.VersionApplier = (*Client)(nil)
+)
+
+// Client calls the JSON API of the gooseglass server at its base URL.
+type Client struct {
+	baseURL  string
+	http     *http.Client
+	timeout  time.Duration
+	token    string
+	user     string
+	password string
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests. The default is http.DefaultClient.
+func WithHTTPClient(c *http.Client) Option {
+	return func(client *Client) { client.http = c }
+}
+
+// WithTimeout bounds each request, including the migrations it runs. The default is no timeout.
+func WithTimeout(d time.Duration) Option {
+	return func(client *Client) { client.timeout = d }
+}
+
+// WithToken sends token as a bearer token.
+func WithToken(token string) Option {
+	return func(client *Client) { client.token = token }
+}
+
+// WithBasicAuth sends the user name and password with every request. Without WithActor the server
+// records the user as the actor of the runs; servers with approvals name the actor themselves.
+func WithBasicAuth(user, password string) Option {
+	return func(client *Client) { client.user, client.password = user, password }
+}
+
+// New returns a Client for the server at baseURL, for example "https://migrations.example.com".
+func New(baseURL string, options ...Option) *Client {
+	c := &Client{baseURL: strings.TrimSuffix(baseURL, "/"), http: http.DefaultClient}
+	for _, o := range options {
+		o(c)
+	}
+	return c
+}
+
+type reasonKey struct{}
+
+// WithReason returns a context whose rollbacks carry reason, which lets them run outside the
+// emergency windows of the server.
+func WithReason(ctx context.Context, reason string) context.Context {
+	return context.WithValue(ctx, reasonKey{}, reason)
+}
+
+// Status returns the status the way goose reports it: missing migrations are pending and
+// untracked ones are left out.
+func (c *Client) Status(ctx context.Context) ([]*goose.MigrationStatus, error) {
+	report, err := c.StatusReport(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*goose.MigrationStatus, 0, len(report.Migrations))
+	for _, m := range report.Migrations {
+		ms := &goose.MigrationStatus{
+			Source: &goose.Source{Type: goose.MigrationType(m.Type), Path: m.Path, Version: m.Version},
+		}
+		switch m.State {
+		case "applied":
+			ms.State = goose.StateApplied
+		case "pending", "missing":
+			// goose reports missing migrations as pending too.
+			ms.State = goose.StatePending
+		default:
+			// Untracked migrations have no source, so goose does not report them. Use StatusReport
+			// to see them.
+			continue
+		}
+		if m.AppliedAt != nil {
+			ms.AppliedAt = *m.AppliedAt
+		}
+		list = append(list, ms)
+	}
+	return list, nil
+}
+
+// StatusReport returns the status as the server reports it, including the environment and
+// whether each migration is missing.
+func (c *Client) StatusReport(ctx context.Context) (gooseglass.StatusReport, error) {
+	var report gooseglass.StatusReport
+	err := c.do(ctx, http.MethodGet, "/api/status", nil, &report)
+	return report, err
+}
+
+// Approval returns the approval request holding a run.
+func (c *Client) Approval(ctx context.Context, id string) (gooseglass.ApprovalRequest, error) {
+	var approval gooseglass.ApprovalRequest
+	err := c.do(ctx, http.MethodGet, "/api/approvals/"+url.PathEscape(id), nil, &approval)
+	return approval, err
+}
+
+// Run returns a recorded run. Runs that changed nothing are not recorded and return ErrNotFound.
+func (c *Client) Run(ctx context.Context, id string) (gooseglass.Run, error) {
+	var run gooseglass.Run
+	err := c.do(ctx, http.MethodGet, "/export/runs/"+url.PathEscape(id)+".json", nil, &run)
+	return run, err
+}
+
+// WaitForApproval polls the approval request every interval until it is done, calling progress
+// whenever its state changes. progress may be nil and interval must be positive.
+func (c *Client) WaitForApproval(ctx context.Context, id string, interval time.Duration, progress func(gooseglass.ApprovalRequest)) (gooseglass.ApprovalRequest, error) {
+	if interval <= 0 {
+		return gooseglass.ApprovalRequest{}, fmt.Errorf("approval poll interval must be positive, got %s", interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var last string
+	for {
+		approval, err := c.Approval(ctx, id)
+		if err != nil {
+			return approval, err
+		}
+		if state := approval.State + "/" + approval.RunID + approval.Error; state != last && progress != nil {
+			progress(approval)
+			last = state
+		}
+		if approval.IsDone() {
+			return approval, nil
+		}
+		select {
+		case <-ctx.Done():
+			return approval, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Client) Up(ctx context.Context) ([]*goose.MigrationResult, error) {
+	return c.run(ctx, "/api/up", nil)
+}
+
+func (c *Client) UpByOne(ctx context.Context) (*goose.MigrationResult, error) {
+	return first(c.run(ctx, "/api/up-by-one", nil))
+}
+
+func (c *Client) UpTo(ctx context.Context, version int64) ([]*goose.MigrationResult, error) {
+	return c.run(ctx, "/api/up-to/"+strconv.FormatInt(version, 10), nil)
+}
+
+func (c *Client) Down(ctx context.Context) (*goose.MigrationResult, error) {
+	return first(c.run(ctx, "/api/down", reasonForm(ctx)))
+}
+
+func (c *Client) DownTo(ctx context.Context, version int64) ([]*goose.MigrationResult, error) {
+	return c.run(ctx, "/api/down-to/"+strconv.FormatInt(version, 10), reasonForm(ctx))
+}
+
+// ApplyVersion applies a missing migration, or rolls one back when direction is false. The server
+// must allow missing migrations.
+func (c *Client) ApplyVersion(ctx context.Context, version int64, direction bool) (*goose.MigrationResult, error) {
+	form := url.Values{}
+	if !direction {
+		form = reasonForm(ctx)
+		form.Set("direction", "down")
+	}
+	return first(c.run(ctx, "/api/apply/"+strconv.FormatInt(version, 10), form))
+}
+
+func reasonForm(ctx context.Context) url.Values {
+	form := url.Values{}
+	if reason, ok := ctx.Value(reasonKey{}).(string); ok && reason != "" {
+		form.Set("override_reason", reason)
+	}
+	return form
+}
+
+func first(results []*goose.MigrationResult, err error) (*goose.MigrationResult, error) {
+	if len(results) == 0 {
+		return nil, err
+	}
+	return results[0], err
+}
+
+// run posts to a run endpoint. A failed migration returns a *goose.PartialError like a local
+// provider does.
+func (c *Client) run(ctx context.Context, path string, form url.Values) ([]*goose.MigrationResult, error) {
+	var report gooseglass.RunReport
+	err := c.do(ctx, http.MethodPost, path, form, &report)
+	var e *Error
+	if errors.As(err, &e) && e.Report.Failed != nil {
+		return nil, &goose.PartialError{
+			Applied: migrationResults(e.Report.Results),
+			Failed:  migrationResult(*e.Report.Failed),
+			Err:     errors.New(e.Report.Error),
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if report.Approval != nil {
+		return nil, &ApprovalPendingError{Approval: *report.Approval}
+	}
+	return migrationResults(report.Results), nil
+}
+
+func migrationResults(list []gooseglass.ReportedResult) []*goose.MigrationResult {
+	results := make([]*goose.MigrationResult, 0, len(list))
+	for _, r := range list {
+		results = append(results, migrationResult(r))
+	}
+	return results
+}
+
+func migrationResult(r gooseglass.ReportedResult) *goose.MigrationResult {
+	result := &goose.MigrationResult{
+		Source:    &goose.Source{Type: goose.MigrationType(r.Type), Path: r.Path, Version: r.Version},
+		Duration:  r.Duration,
+		Direction: r.Direction,
+		Empty:     r.Empty,
+	}
+	if r.Error != "" {
+		result.Error = errors.New(r.Error)
+	}
+	return result
+}
+
+func (c *Client) do(ctx context.Context, method, path string, form url.Values, v any) error {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+	request, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "application/json")
+	if form != nil {
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if c.token != "" {
+		request.Header.Set("Authorization", "Bearer "+c.token)
+	} else if c.user != "" {
+		request.SetBasicAuth(c.user, c.password)
+	}
+	response, err := c.http.Do(request)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = response.Body.Close() }()
+	buf, err := io.ReadAll(io.LimitReader(response.Body, 16<<20))
+	if err != nil {
+		return err
+	}
+	if response.StatusCode >= 300 {
+		return newError(method, path, response.StatusCode, buf)
+	}
+	if err := json.Unmarshal(buf, v); err != nil {
+		return fmt.Errorf("%s %s: decoding response: %w", method, path, err)
+	}
+	return nil
+}
+
+// newError reads the error report from the body of a failed response. Bodies that are not JSON,
+// for example from a proxy, become the message.
+func newError(method, path string, code int, body []byte) *Error {
+	e := &Error{Method: method, Path: path, StatusCode: code}
+	if err := json.Unmarshal(body, &e.Report); err != nil || e.Report.Error == "" {
+		e.Report = gooseglass.RunReport{Error: strings.TrimSpace(string(bytes.ToValidUTF8(body, nil)))}
+	}
+	return e
+}
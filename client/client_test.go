@@ -0,0 +1,239 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/typelate/dom/domtest"
+
+	"github.com/crhntr/gooseglass"
+	"github.com/crhntr/gooseglass/client"
+	"github.com/crhntr/gooseglass/internal/fake"
+)
+
+func newServer(t *testing.T, provider *fake.Provider, options ...gooseglass.Option) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	gooseglass.Pages(mux, provider, options...)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func result(version int64, direction string, err error) *goose.MigrationResult {
+	return &goose.MigrationResult{
+		Source:    &goose.Source{Type: goose.TypeSQL, Path: fmt.Sprintf("%02d_migration.sql", version), Version: version},
+		Duration:  time.Second,
+		Direction: direction,
+		Error:     err,
+	}
+}
+
+func TestClient_Status(t *testing.T) {
+	provider := new(fake.Provider)
+	at := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	provider.StatusReturns([]*goose.MigrationStatus{
+		{Source: &goose.Source{Type: goose.TypeSQL, Path: "01_init.sql", Version: 1}, State: goose.StateApplied, AppliedAt: at},
+		{Source: &goose.Source{Type: goose.TypeGo, Path: "02_seed.go", Version: 2}, State: goose.StatePending},
+	}, nil)
+	srv := newServer(t, provider)
+
+	list, err := client.New(srv.URL).Status(t.Context())
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, goose.StateApplied, list[0].State)
+	assert.True(t, at.Equal(list[0].AppliedAt))
+	assert.Equal(t, &goose.Source{Type: goose.TypeGo, Path: "02_seed.go", Version: 2}, list[1].Source)
+	assert.Equal(t, goose.StatePending, list[1].State)
+}
+
+func TestClient_Status_missingAndUntracked(t *testing.T) {
+	provider := new(fake.Provider)
+	at := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	provider.StatusReturns([]*goose.MigrationStatus{
+		{Source: &goose.Source{Type: goose.TypeSQL, Path: "01_init.sql", Version: 1}, State: goose.StateApplied, AppliedAt: at},
+		{Source: &goose.Source{Type: goose.TypeSQL, Path: "02_users.sql", Version: 2}, State: goose.StatePending},
+		{Source: &goose.Source{Type: goose.TypeSQL, Path: "03_posts.sql", Version: 3}, State: goose.StateApplied, AppliedAt: at},
+		{Source: &goose.Source{Version: 4}, State: "untracked", AppliedAt: at},
+	}, nil)
+	srv := newServer(t, provider)
+	c := client.New(srv.URL)
+
+	report, err := c.StatusReport(t.Context())
+	require.NoError(t, err)
+	require.Len(t, report.Migrations, 4)
+	assert.Equal(t, "missing", report.Migrations[1].State)
+	assert.Equal(t, "untracked", report.Migrations[3].State)
+
+	list, err := c.Status(t.Context())
+	require.NoError(t, err)
+	require.Len(t, list, 3, "untracked migrations are left out")
+	assert.Equal(t, goose.StatePending, list[1].State, "missing migrations are pending, like goose reports them")
+
+	mux := http.NewServeMux()
+	gooseglass.Pages(mux, new(fake.Provider), gooseglass.WithPeers(gooseglass.LocalPeer("staging", c)))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, gooseglass.TemplateRoutePaths{}.Compare(), nil))
+	document := domtest.ParseResponseDocument(t, rec.Result())
+	assert.NotNil(t, document.QuerySelector(`#comparison tr[data-version="2"] td[data-state="missing"]`))
+	assert.NotNil(t, document.QuerySelector(`#comparison tr[data-version="4"] td[data-state="untracked"]`), "LocalPeer uses the status report")
+}
+
+func TestClient_Up(t *testing.T) {
+	provider := new(fake.Provider)
+	provider.UpReturns([]*goose.MigrationResult{result(1, "up", nil), result(2, "up", nil)}, nil)
+	srv := newServer(t, provider)
+
+	results, err := client.New(srv.URL).Up(t.Context())
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, int64(2), results[1].Source.Version)
+	assert.Equal(t, "up", results[1].Direction)
+	assert.Equal(t, time.Second, results[1].Duration)
+}
+
+func TestClient_UpTo(t *testing.T) {
+	provider := new(fake.Provider)
+	provider.UpToReturns([]*goose.MigrationResult{result(3, "up", nil)}, nil)
+	srv := newServer(t, provider)
+
+	_, err := client.New(srv.URL).UpTo(t.Context(), 3)
+	require.NoError(t, err)
+	require.Equal(t, 1, provider.UpToCallCount())
+	_, version := provider.UpToArgsForCall(0)
+	assert.Equal(t, int64(3), version)
+}
+
+func TestClient_DownTo_reason(t *testing.T) {
+	provider := new(fake.Provider)
+	provider.DownToReturns([]*goose.MigrationResult{result(2, "down", nil)}, nil)
+	var plan gooseglass.Plan
+	srv := newServer(t, provider, gooseglass.WithBeforeMigrate(func(_ context.Context, p gooseglass.Plan) error {
+		plan = p
+		return nil
+	}))
+
+	ctx := client.WithReason(t.Context(), "bad deploy")
+	results, err := client.New(srv.URL, client.WithBasicAuth("ada", "secret")).DownTo(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "bad deploy", plan.Reason)
+	assert.Equal(t, "ada", plan.Actor)
+}
+
+func TestClient_partialError(t *testing.T) {
+	provider := new(fake.Provider)
+	failed := result(2, "up", errors.New("syntax error"))
+	provider.UpReturns(nil, &goose.PartialError{Applied: []*goose.MigrationResult{result(1, "up", nil)}, Failed: failed, Err: failed.Error})
+	srv := newServer(t, provider)
+
+	_, err := client.New(srv.URL).Up(t.Context())
+	var partial *goose.PartialError
+	require.ErrorAs(t, err, &partial)
+	require.Len(t, partial.Applied, 1)
+	assert.Equal(t, int64(2), partial.Failed.Source.Version)
+	assert.EqualError(t, partial.Err, "syntax error")
+}
+
+func TestClient_errors(t *testing.T) {
+	t.Run("goose errors", func(t *testing.T) {
+		provider := new(fake.Provider)
+		provider.UpByOneReturns(nil, goose.ErrNoNextVersion)
+		srv := newServer(t, provider)
+
+		_, err := client.New(srv.URL).UpByOne(t.Context())
+		assert.ErrorIs(t, err, goose.ErrNoNextVersion)
+		var e *client.Error
+		require.ErrorAs(t, err, &e)
+		assert.Equal(t, http.StatusInternalServerError, e.StatusCode)
+		assert.Equal(t, "no_next_version", e.Report.GooseError)
+	})
+	t.Run("an error matching several goose errors reports the first", func(t *testing.T) {
+		provider := new(fake.Provider)
+		provider.ApplyVersionReturns(nil, errors.Join(goose.ErrNotApplied, goose.ErrVersionNotFound))
+		srv := newServer(t, provider, gooseglass.WithAllowMissing(true))
+
+		for range 10 {
+			_, err := client.New(srv.URL).ApplyVersion(t.Context(), 1, true)
+			var e *client.Error
+			require.ErrorAs(t, err, &e)
+			assert.Equal(t, "version_not_found", e.Report.GooseError)
+			assert.ErrorIs(t, err, goose.ErrVersionNotFound)
+		}
+	})
+	t.Run("maintenance window", func(t *testing.T) {
+		window, err := gooseglass.ParseMaintenanceWindow("0 0 31 2 *", time.Hour, time.UTC)
+		require.NoError(t, err)
+		srv := newServer(t, new(fake.Provider), gooseglass.WithMaintenanceWindows(window))
+
+		_, err = client.New(srv.URL).Up(t.Context())
+		assert.ErrorIs(t, err, client.ErrWindowClosed)
+	})
+	t.Run("apply not allowed", func(t *testing.T) {
+		srv := newServer(t, new(fake.Provider))
+
+		_, err := client.New(srv.URL).ApplyVersion(t.Context(), 1, true)
+		assert.ErrorIs(t, err, client.ErrForbidden)
+	})
+	t.Run("not json", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+		}))
+		t.Cleanup(srv.Close)
+
+		_, err := client.New(srv.URL).Status(t.Context())
+		var e *client.Error
+		require.ErrorAs(t, err, &e)
+		assert.Equal(t, http.StatusBadGateway, e.StatusCode)
+		assert.Equal(t, "bad gateway", e.Report.Error)
+	})
+}
+
+func TestClient_approval(t *testing.T) {
+	provider := new(fake.Provider)
+	srv := newServer(t, provider,
+		gooseglass.WithApprovals(gooseglass.NewApprovals(time.Hour, nil)),
+		gooseglass.WithActor(func(r *http.Request) string { return r.Header.Get("X-User") }),
+	)
+
+	_, err := client.New(srv.URL).Up(t.Context())
+	var pending *client.ApprovalPendingError
+	require.ErrorAs(t, err, &pending)
+	assert.NotEmpty(t, pending.Approval.ID)
+	assert.Zero(t, provider.UpCallCount())
+
+	_, err = client.New(srv.URL).WaitForApproval(t.Context(), pending.Approval.ID, 0, nil)
+	assert.ErrorContains(t, err, "must be positive")
+}
+
+func TestClient_auth(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"migrations":[]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	_, err := client.New(srv.URL, client.WithToken("s3cret")).Status(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer s3cret", got)
+}
+
+func TestClient_timeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(srv.Close)
+
+	_, err := client.New(srv.URL, client.WithTimeout(10*time.Millisecond)).Status(t.Context())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
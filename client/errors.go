@@ -0,0 +1,57 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/crhntr/gooseglass"
+	"github.com/crhntr/gooseglass/internal/gooseerr"
+)
+
+// Errors matched by errors.Is against an *Error, by status code.
+var (
+	ErrUnauthorized  = errors.New("unauthorized")
+	ErrForbidden     = errors.New("forbidden")
+	ErrNotFound      = errors.New("not found")
+	ErrRunInProgress = errors.New("another run is in progress")
+	ErrWindowClosed  = errors.New("blocked by maintenance window policy")
+)
+
+var statusErrors = map[int]error{
+	http.StatusUnauthorized: ErrUnauthorized,
+	http.StatusForbidden:    ErrForbidden,
+	http.StatusNotFound:     ErrNotFound,
+	http.StatusConflict:     ErrRunInProgress,
+	http.StatusLocked:       ErrWindowClosed,
+}
+
+// Error is a response from the server with an error status. Use errors.Is with the Err variables
+// above or the goose errors, such as goose.ErrNoNextVersion, to tell them apart.
+type Error struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Report     gooseglass.RunReport
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s %s: %d %s: %s", e.Method, e.Path, e.StatusCode, http.StatusText(e.StatusCode), e.Report.Error)
+}
+
+func (e *Error) Is(target error) bool {
+	if err, ok := statusErrors[e.StatusCode]; ok && err == target {
+		return true
+	}
+	err := gooseerr.ByName(e.Report.GooseError)
+	return err != nil && err == target
+}
+
+// ApprovalPendingError is returned when the server holds the run for a second person to approve.
+type ApprovalPendingError struct {
+	Approval gooseglass.ApprovalRequest
+}
+
+func (e *ApprovalPendingError) Error() string {
+	return fmt.Sprintf("%s is waiting for approval %s", e.Approval.Plan, e.Approval.ID)
+}
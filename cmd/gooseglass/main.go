@@ -0,0 +1,274 @@
+// Command gooseglass drives a running gooseglass server from the command line, for example from a
+// CI pipeline.
+//
+//	gooseglass remote status|up|up-to N|down|down-to N [flags]
+//
+// The server URL and token default to the GOOSEGLASS_URL and GOOSEGLASS_TOKEN environment
+// variables. The exit code is 1 when a command fails, 2 for usage errors, 3 when
+// --fail-on-pending is set and migrations are pending and 4 when a run is held for approval and
+// --wait is not set.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pressly/goose/v3"
+
+	"github.com/crhntr/gooseglass"
+	"github.com/crhntr/gooseglass/client"
+)
+
+const (
+	exitFailure = 1
+	exitUsage   = 2
+	exitPending = 3
+	// exitAwaitingApproval means the run was held for approval and has not run yet.
+	exitAwaitingApproval = 4
+)
+
+const usage = `usage: gooseglass remote status|up|up-to N|down|down-to N [flags]
+
+exit codes: 1 failed, 2 usage error, 3 migrations pending (--fail-on-pending),
+4 held for approval (without --wait)`
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) < 2 || args[0] != "remote" {
+		_, _ = fmt.Fprintln(stderr, usage)
+		return exitUsage
+	}
+	command := args[1]
+
+	flags := flag.NewFlagSet("gooseglass remote "+command, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	serverURL := flags.String("url", os.Getenv("GOOSEGLASS_URL"), "base URL of the gooseglass server")
+	token := flags.String("token", os.Getenv("GOOSEGLASS_TOKEN"), "bearer token sent to the server")
+	timeout := flags.Duration("timeout", 0, "give up on a request after this long (0 waits forever)")
+	asJSON := flags.Bool("json", false, "print JSON instead of a table")
+	failOnPending := flags.Bool("fail-on-pending", false, "status exits 3 when migrations are pending")
+	reason := flags.String("reason", "", "reason for a rollback outside the emergency windows")
+	wait := flags.Bool("wait", false, "wait for a run held for approval to finish")
+	poll := flags.Duration("poll", 2*time.Second, "how often --wait checks the approval")
+	positional, err := parseInterspersed(flags, args[2:])
+	if err != nil {
+		return exitUsage
+	}
+	if *serverURL == "" {
+		_, _ = fmt.Fprintln(stderr, "gooseglass: --url or GOOSEGLASS_URL is required")
+		return exitUsage
+	}
+	if *poll <= 0 {
+		_, _ = fmt.Fprintln(stderr, "gooseglass: --poll must be positive")
+		return exitUsage
+	}
+
+	var version int64
+	switch command {
+	case "status", "up", "down":
+		if len(positional) != 0 {
+			_, _ = fmt.Fprintln(stderr, usage)
+			return exitUsage
+		}
+	case "up-to", "down-to":
+		if len(positional) != 1 {
+			_, _ = fmt.Fprintln(stderr, usage)
+			return exitUsage
+		}
+		if version, err = strconv.ParseInt(positional[0], 10, 64); err != nil {
+			_, _ = fmt.Fprintf(stderr, "gooseglass: invalid version %q\n", positional[0])
+			return exitUsage
+		}
+	default:
+		_, _ = fmt.Fprintln(stderr, usage)
+		return exitUsage
+	}
+
+	options := []client.Option{client.WithTimeout(*timeout)}
+	if *token != "" {
+		options = append(options, client.WithToken(*token))
+	}
+	c := client.New(*serverURL, options...)
+	if *reason != "" {
+		ctx = client.WithReason(ctx, *reason)
+	}
+
+	if command == "status" {
+		return status(ctx, c, stdout, stderr, *asJSON, *failOnPending)
+	}
+	var results []*goose.MigrationResult
+	switch command {
+	case "up":
+		results, err = c.Up(ctx)
+	case "up-to":
+		results, err = c.UpTo(ctx, version)
+	case "down":
+		var result *goose.MigrationResult
+		result, err = c.Down(ctx)
+		if result != nil {
+			results = append(results, result)
+		}
+	case "down-to":
+		results, err = c.DownTo(ctx, version)
+	}
+	var pending *client.ApprovalPendingError
+	if errors.As(err, &pending) {
+		return waitForApproval(ctx, c, stdout, stderr, pending.Approval, *wait, *poll, *asJSON)
+	}
+	var partial *goose.PartialError
+	if errors.As(err, &partial) {
+		results = append(partial.Applied, partial.Failed)
+	}
+	printEntries(stdout, entries(results), *asJSON)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "gooseglass: %s failed: %v\n", command, err)
+		return exitFailure
+	}
+	return 0
+}
+
+// parseInterspersed parses flags that come before, after or around the positional arguments.
+func parseInterspersed(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func status(ctx context.Context, c *client.Client, stdout, stderr io.Writer, asJSON, failOnPending bool) int {
+	report, err := c.StatusReport(ctx)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "gooseglass: status failed: %v\n", err)
+		return exitFailure
+	}
+	if asJSON {
+		writeJSON(stdout, report)
+	} else {
+		w := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "VERSION\tSTATE\tAPPLIED AT\tPATH")
+		for _, m := range report.Migrations {
+			appliedAt := "-"
+			if m.AppliedAt != nil {
+				appliedAt = m.AppliedAt.UTC().Format(time.RFC3339)
+			}
+			_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", m.Version, m.State, appliedAt, m.Path)
+		}
+		_ = w.Flush()
+	}
+	if failOnPending {
+		for _, m := range report.Migrations {
+			if m.State == "pending" || m.State == "missing" {
+				_, _ = fmt.Fprintln(stderr, "gooseglass: migrations are pending")
+				return exitPending
+			}
+		}
+	}
+	return 0
+}
+
+// waitForApproval reports a run held for approval and, when asked to, streams its progress until
+// it finishes and prints what it ran.
+func waitForApproval(ctx context.Context, c *client.Client, stdout, stderr io.Writer, approval gooseglass.ApprovalRequest, wait bool, poll time.Duration, asJSON bool) int {
+	_, _ = fmt.Fprintf(stderr, "gooseglass: %s is waiting for approval %s\n", approval.Plan, approval.ID)
+	if !wait {
+		return exitAwaitingApproval
+	}
+	approval, err := c.WaitForApproval(ctx, approval.ID, poll, func(a gooseglass.ApprovalRequest) {
+		switch {
+		case a.IsPending():
+			_, _ = fmt.Fprintf(stderr, "gooseglass: approval %s pending until %s\n", a.ID, a.ExpiresAt.UTC().Format(time.RFC3339))
+		case a.IsDone():
+			_, _ = fmt.Fprintf(stderr, "gooseglass: approval %s %s by %s\n", a.ID, a.State, a.DecidedBy)
+		default:
+			_, _ = fmt.Fprintf(stderr, "gooseglass: approved by %s, running %s\n", a.DecidedBy, a.Plan)
+		}
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "gooseglass: waiting for approval failed: %v\n", err)
+		return exitFailure
+	}
+	if approval.RunID == "" && approval.Error == "" {
+		// Rejected or expired.
+		return exitFailure
+	}
+	list := []gooseglass.LedgerEntry{}
+	if approval.RunID != "" {
+		run, err := c.Run(ctx, approval.RunID)
+		if err != nil && !errors.Is(err, client.ErrNotFound) {
+			_, _ = fmt.Fprintf(stderr, "gooseglass: reading run %s failed: %v\n", approval.RunID, err)
+			return exitFailure
+		}
+		list = append(list, run.Entries...)
+	}
+	printEntries(stdout, list, asJSON)
+	if approval.Error != "" {
+		_, _ = fmt.Fprintf(stderr, "gooseglass: %s failed: %s\n", approval.Plan, approval.Error)
+		return exitFailure
+	}
+	return 0
+}
+
+func entries(results []*goose.MigrationResult) []gooseglass.LedgerEntry {
+	list := make([]gooseglass.LedgerEntry, 0, len(results))
+	for _, result := range results {
+		if result == nil || result.Source == nil {
+			continue
+		}
+		entry := gooseglass.LedgerEntry{Version: result.Source.Version, Path: result.Source.Path, Direction: result.Direction, Duration: result.Duration}
+		if result.Error != nil {
+			entry.Error = result.Error.Error()
+		}
+		list = append(list, entry)
+	}
+	return list
+}
+
+func printEntries(stdout io.Writer, list []gooseglass.LedgerEntry, asJSON bool) {
+	if asJSON {
+		writeJSON(stdout, list)
+		return
+	}
+	if len(list) == 0 {
+		_, _ = fmt.Fprintln(stdout, "no migrations to run")
+		return
+	}
+	w := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "RESULT\tDIRECTION\tVERSION\tDURATION\tPATH")
+	for _, entry := range list {
+		result := "OK"
+		if entry.Error != "" {
+			result = "FAILED"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n", result, entry.Direction, entry.Version, entry.Duration, entry.Path)
+	}
+	_ = w.Flush()
+}
+
+func writeJSON(w io.Writer, v any) {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
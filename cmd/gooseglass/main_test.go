@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/crhntr/gooseglass"
+	"github.com/crhntr/gooseglass/internal/fake"
+)
+
+func newServer(t *testing.T, provider *fake.Provider, options ...gooseglass.Option) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	gooseglass.Pages(mux, provider, options...)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func source(version int64) *goose.Source {
+	return &goose.Source{Type: goose.TypeSQL, Path: fmt.Sprintf("%02d_migration.sql", version), Version: version}
+}
+
+func runCLI(t *testing.T, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+	code := run(ctx, args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRemoteStatus(t *testing.T) {
+	provider := new(fake.Provider)
+	provider.StatusReturns([]*goose.MigrationStatus{
+		{Source: source(1), State: goose.StateApplied, AppliedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{Source: source(2), State: goose.StatePending},
+	}, nil)
+	srv := newServer(t, provider)
+
+	code, stdout, _ := runCLI(t, "remote", "status", "--url", srv.URL)
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "VERSION")
+	assert.Contains(t, stdout, "2025-01-02T03:04:05Z")
+	assert.Regexp(t, `2\s+pending\s+-\s+02_migration.sql`, stdout)
+
+	code, _, stderr := runCLI(t, "remote", "status", "--url", srv.URL, "--fail-on-pending")
+	assert.Equal(t, exitPending, code)
+	assert.Contains(t, stderr, "pending")
+
+	code, stdout, _ = runCLI(t, "remote", "status", "--url", srv.URL, "--json")
+	assert.Equal(t, 0, code)
+	var report gooseglass.StatusReport
+	require.NoError(t, json.Unmarshal([]byte(stdout), &report))
+	assert.Len(t, report.Migrations, 2)
+}
+
+func TestRemoteUpTo(t *testing.T) {
+	provider := new(fake.Provider)
+	provider.UpToReturns([]*goose.MigrationResult{{Source: source(2), Direction: "up", Duration: time.Second}}, nil)
+	srv := newServer(t, provider)
+
+	code, stdout, _ := runCLI(t, "remote", "up-to", "2", "--url", srv.URL, "--json")
+	assert.Equal(t, 0, code)
+	_, version := provider.UpToArgsForCall(0)
+	assert.Equal(t, int64(2), version)
+	var list []gooseglass.LedgerEntry
+	require.NoError(t, json.Unmarshal([]byte(stdout), &list))
+	require.Len(t, list, 1)
+	assert.Equal(t, "02_migration.sql", list[0].Path)
+}
+
+func TestRemoteUp_failure(t *testing.T) {
+	provider := new(fake.Provider)
+	failed := &goose.MigrationResult{Source: source(2), Direction: "up", Error: errors.New("syntax error")}
+	provider.UpReturns(nil, &goose.PartialError{
+		Applied: []*goose.MigrationResult{{Source: source(1), Direction: "up"}},
+		Failed:  failed,
+		Err:     failed.Error,
+	})
+	srv := newServer(t, provider)
+
+	code, stdout, stderr := runCLI(t, "remote", "up", "--url", srv.URL)
+	assert.Equal(t, exitFailure, code)
+	assert.Regexp(t, `OK\s+up\s+1`, stdout)
+	assert.Regexp(t, `FAILED\s+up\s+2`, stdout)
+	assert.Contains(t, stderr, "syntax error")
+}
+
+func TestRemoteDown_reason(t *testing.T) {
+	provider := new(fake.Provider)
+	provider.DownReturns(&goose.MigrationResult{Source: source(1), Direction: "down"}, nil)
+	var plan gooseglass.Plan
+	srv := newServer(t, provider, gooseglass.WithBeforeMigrate(func(_ context.Context, p gooseglass.Plan) error {
+		plan = p
+		return nil
+	}))
+
+	code, stdout, _ := runCLI(t, "remote", "down", "--url", srv.URL, "--reason", "hotfix")
+	assert.Equal(t, 0, code)
+	assert.Regexp(t, `OK\s+down\s+1`, stdout)
+	assert.Equal(t, "hotfix", plan.Reason)
+}
+
+func TestRemoteUsage(t *testing.T) {
+	for _, args := range [][]string{
+		{},
+		{"remote"},
+		{"remote", "sideways", "--url", "http://example.com"},
+		{"remote", "up-to", "--url", "http://example.com"},
+		{"remote", "up-to", "two", "--url", "http://example.com"},
+		{"remote", "status"},
+		{"remote", "up", "--url", "http://example.com", "--wait", "--poll", "0s"},
+	} {
+		code, _, _ := runCLI(t, args...)
+		assert.Equal(t, exitUsage, code, args)
+	}
+}
+
+func TestRemoteUp_heldForApproval(t *testing.T) {
+	provider := new(fake.Provider)
+	srv := newServer(t, provider,
+		gooseglass.WithApprovals(gooseglass.NewApprovals(time.Hour, nil)),
+		gooseglass.WithActor(func(r *http.Request) string { return r.Header.Get("X-User") }),
+	)
+
+	code, _, stderr := runCLI(t, "remote", "up", "--url", srv.URL)
+	assert.Equal(t, exitAwaitingApproval, code)
+	assert.Contains(t, stderr, "waiting for approval")
+	assert.Zero(t, provider.UpCallCount())
+}
+
+func TestRemoteUp_waitForApproval(t *testing.T) {
+	provider := new(fake.Provider)
+	provider.UpReturns([]*goose.MigrationResult{{Source: source(1), Direction: "up", Duration: time.Second}}, nil)
+	srv := newServer(t, provider,
+		gooseglass.WithApprovals(gooseglass.NewApprovals(time.Hour, nil)),
+		gooseglass.WithActor(func(r *http.Request) string { return r.Header.Get("X-User") }),
+	)
+
+	// Approve the request from another user as soon as it shows up on the approvals page.
+	go func() {
+		for range 100 {
+			time.Sleep(10 * time.Millisecond)
+			req, _ := http.NewRequest(http.MethodGet, srv.URL+"/approvals", nil)
+			req.Header.Set("X-User", "grace")
+			res, err := http.DefaultClient.Do(req)
+			if err != nil {
+				continue
+			}
+			var body bytes.Buffer
+			_, _ = body.ReadFrom(res.Body)
+			_ = res.Body.Close()
+			_, rest, ok := strings.Cut(body.String(), "/approvals/")
+			if !ok {
+				continue
+			}
+			id, _, _ := strings.Cut(rest, "/approve")
+			req, _ = http.NewRequest(http.MethodPost, srv.URL+"/approvals/"+url.PathEscape(id)+"/approve", nil)
+			req.Header.Set("X-User", "grace")
+			if res, err := http.DefaultClient.Do(req); err == nil {
+				_ = res.Body.Close()
+			}
+			return
+		}
+	}()
+
+	code, stdout, stderr := runCLI(t, "remote", "up", "--url", srv.URL, "--wait", "--poll", "10ms")
+	assert.Equal(t, 0, code, stderr)
+	assert.Contains(t, stderr, "waiting for approval")
+	assert.Contains(t, stderr, "approved by grace")
+	assert.Regexp(t, `OK\s+up\s+1`, stdout)
+	assert.Equal(t, 1, provider.UpCallCount())
+}
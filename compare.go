@@ -0,0 +1,152 @@
+package gooseglass
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// peerTimeout bounds how long the comparison page waits for each peer.
+const peerTimeout = 10 * time.Second
+
+// Peer is an environment shown on the comparison page.
+type Peer struct {
+	Name   string
+	status func(ctx context.Context) (StatusReport, error)
+}
+
+// RemotePeer reads the status of the gooseglass server at baseURL from its JSON status endpoint. A
+// nil client uses http.DefaultClient.
+func RemotePeer(name, baseURL string, client *http.Client) Peer {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	endpoint := strings.TrimSuffix(baseURL, "/") + apiStatusPath
+	return Peer{Name: name, status: func(ctx context.Context) (StatusReport, error) {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return StatusReport{}, err
+		}
+		request.Header.Set("Accept", "application/json")
+		response, err := client.Do(request)
+		if err != nil {
+			return StatusReport{}, err
+		}
+		defer func() { _ = response.Body.Close() }()
+		if response.StatusCode != http.StatusOK {
+			return StatusReport{}, fmt.Errorf("GET %s: %s", endpoint, response.Status)
+		}
+		var report StatusReport
+		if err := json.NewDecoder(response.Body).Decode(&report); err != nil {
+			return StatusReport{}, fmt.Errorf("GET %s: %w", endpoint, err)
+		}
+		return report, nil
+	}}
+}
+
+// LocalPeer reads the status from a provider in this process. Providers that report the status
+// themselves, like client.Client, are asked for their StatusReport so missing and untracked
+// migrations keep their state.
+func LocalPeer(name string, provider Provider) Peer {
+	return Peer{Name: name, status: func(ctx context.Context) (StatusReport, error) {
+		if reporter, ok := provider.(interface {
+			StatusReport(ctx context.Context) (StatusReport, error)
+		}); ok {
+			return reporter.StatusReport(ctx)
+		}
+		list, err := provider.Status(ctx)
+		if err != nil {
+			return StatusReport{}, err
+		}
+		return newStatusReport(newStatusTable(list, statusQuery{}, false, 0), nil), nil
+	}}
+}
+
+// WithPeers adds a page comparing the migrations of the peers in promotion order, for example dev,
+// staging then prod. A migration applied in a peer but not in the one after it is highlighted as
+// what promoting to that peer will run. Add a LocalPeer to include this server's own provider.
+func WithPeers(peers ...Peer) Option {
+	return func(s *server) { s.peers = append(s.peers, peers...) }
+}
+
+type comparison struct {
+	Peers []comparedPeer
+	Rows  []comparisonRow
+}
+
+type comparedPeer struct {
+	Name      string
+	DBVersion int64
+	Err       error
+}
+
+type comparisonRow struct {
+	Version int64
+	Path    string
+	Cells   []comparisonCell
+}
+
+// comparisonCell is the state of a migration in one peer. State is empty when the peer does not
+// know the migration. Promote marks a migration that promoting from the previous peer will run.
+type comparisonCell struct {
+	State   string
+	Promote bool
+}
+
+// Drift reports whether the peers disagree about the migration.
+func (row comparisonRow) Drift() bool {
+	for _, cell := range row.Cells {
+		if cell.State != row.Cells[0].State {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *server) Compare(ctx context.Context) (comparison, error) {
+	if len(s.peers) == 0 {
+		return comparison{}, statusError{code: http.StatusNotFound, err: errors.New("no peers configured")}
+	}
+	reports := make([]StatusReport, len(s.peers))
+	result := comparison{Peers: make([]comparedPeer, len(s.peers))}
+	var wg sync.WaitGroup
+	for i, peer := range s.peers {
+		wg.Go(func() {
+			ctx, cancel := context.WithTimeout(ctx, peerTimeout)
+			defer cancel()
+			report, err := peer.status(ctx)
+			reports[i] = report
+			result.Peers[i] = comparedPeer{Name: peer.Name, DBVersion: report.DBVersion, Err: err}
+		})
+	}
+	wg.Wait()
+
+	rows := make(map[int64]*comparisonRow)
+	for i, report := range reports {
+		for _, m := range report.Migrations {
+			row, ok := rows[m.Version]
+			if !ok {
+				row = &comparisonRow{Version: m.Version, Cells: make([]comparisonCell, len(reports))}
+				rows[m.Version] = row
+			}
+			row.Path = cmp.Or(row.Path, m.Path)
+			row.Cells[i].State = m.State
+		}
+	}
+	for _, row := range rows {
+		for i := 1; i < len(row.Cells); i++ {
+			row.Cells[i].Promote = result.Peers[i].Err == nil &&
+				row.Cells[i-1].State == kindApplied && row.Cells[i].State != kindApplied
+		}
+		result.Rows = append(result.Rows, *row)
+	}
+	slices.SortFunc(result.Rows, func(a, b comparisonRow) int { return cmp.Compare(a.Version, b.Version) })
+	return result, nil
+}
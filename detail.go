@@ -0,0 +1,60 @@
+package gooseglass
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pressly/goose/v3"
+)
+
+const (
+	sparklineWidth  = 120
+	sparklineHeight = 24
+)
+
+type migrationDetail struct {
+	Version int64
+	Status  *goose.MigrationStatus
+	History []LedgerEntry
+}
+
+// upDurations returns the durations of the successful up runs, oldest first.
+func (detail migrationDetail) upDurations() []time.Duration {
+	var list []time.Duration
+	for _, entry := range detail.History {
+		if entry.Direction == "up" && entry.Error == "" {
+			list = append(list, entry.Duration)
+		}
+	}
+	return list
+}
+
+// Sparkline returns the points of an SVG polyline of the up run durations scaled to
+// SparklineWidth by SparklineHeight. It is empty with fewer than two runs.
+func (detail migrationDetail) Sparkline() string {
+	durations := detail.upDurations()
+	if len(durations) < 2 {
+		return ""
+	}
+	slowest := max(detail.Slowest(), 1)
+	points := make([]string, 0, len(durations))
+	for i, d := range durations {
+		x := float64(i) * sparklineWidth / float64(len(durations)-1)
+		y := sparklineHeight - float64(d)*sparklineHeight/float64(slowest)
+		points = append(points, fmt.Sprintf("%.1f,%.1f", x, y))
+	}
+	return strings.Join(points, " ")
+}
+
+// Slowest returns the longest successful up run.
+func (detail migrationDetail) Slowest() time.Duration {
+	var slowest time.Duration
+	for _, d := range detail.upDurations() {
+		slowest = max(slowest, d)
+	}
+	return slowest
+}
+
+func (detail migrationDetail) SparklineWidth() int  { return sparklineWidth }
+func (detail migrationDetail) SparklineHeight() int { return sparklineHeight }
@@ -0,0 +1,191 @@
+package gooseglass
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"go/token"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DevMode lets developers create migration files from the status page. Do not enable it in
+// production.
+type DevMode struct {
+	// Dir is the writable directory holding the migration sources.
+	Dir string
+	// Sequential numbers new files after the highest existing version (00001_name.sql) instead of
+	// by UTC timestamp (20060102150405_name.sql), like goose create -s.
+	Sequential bool
+}
+
+// WithDevMode adds the new migration form to the status page.
+func WithDevMode(dev DevMode) Option {
+	return func(s *server) { s.dev = &dev }
+}
+
+type newMigrationForm struct {
+	Name string `name:"name"`
+	Type string `name:"type"`
+}
+
+type newMigration struct {
+	Path string
+	// RebuildRequired is set for Go migrations, which only run after the program is rebuilt.
+	RebuildRequired bool
+}
+
+const (
+	migrationTypeSQL = "sql"
+	migrationTypeGo  = "go"
+)
+
+func (s *server) NewMigration(_ context.Context, form newMigrationForm) (newMigration, error) {
+	if s.dev == nil {
+		return newMigration{}, statusError{code: http.StatusNotFound, err: errors.New("dev mode is not enabled")}
+	}
+	name := snakeCase(form.Name)
+	if name == "" {
+		return newMigration{}, statusError{code: http.StatusBadRequest, err: errors.New("a migration name is required")}
+	}
+	kind := cmp.Or(form.Type, migrationTypeSQL)
+	if kind != migrationTypeSQL && kind != migrationTypeGo {
+		return newMigration{}, statusError{code: http.StatusBadRequest, err: fmt.Errorf("unknown migration type %q", form.Type)}
+	}
+	version, err := s.dev.nextVersion(s.now())
+	if err != nil {
+		return newMigration{}, err
+	}
+	fileName := version + "_" + name + "." + kind
+	body := sqlMigrationSkeleton
+	if kind == migrationTypeGo {
+		body = goMigrationSkeleton(goPackageName(s.dev.Dir), camelCase(name))
+	}
+	path := filepath.Join(s.dev.Dir, fileName)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return newMigration{}, err
+	}
+	if _, err := f.WriteString(body); err != nil {
+		return newMigration{}, errors.Join(err, f.Close())
+	}
+	if err := f.Close(); err != nil {
+		return newMigration{}, err
+	}
+	if kind == migrationTypeGo {
+		// Go migrations register themselves when the program starts, so reloading cannot pick
+		// them up.
+		return newMigration{Path: fileName, RebuildRequired: true}, nil
+	}
+	if reloading, ok := s.provider.(*ReloadingProvider); ok {
+		// Reload now rather than waiting for Watch so the refreshed table shows the new file. The
+		// reload notifies the open pages.
+		_, err := reloading.Reload()
+		return newMigration{Path: fileName}, err
+	}
+	s.hub.broadcast(eventRefreshMigration)
+	return newMigration{Path: fileName}, nil
+}
+
+func (dev *DevMode) nextVersion(now time.Time) (string, error) {
+	if !dev.Sequential {
+		return now.UTC().Format("20060102150405"), nil
+	}
+	entries, err := os.ReadDir(dev.Dir)
+	if err != nil {
+		return "", err
+	}
+	var highest int64
+	for _, entry := range entries {
+		prefix, _, ok := strings.Cut(entry.Name(), "_")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		if n, err := strconv.ParseInt(prefix, 10, 64); err == nil {
+			highest = max(highest, n)
+		}
+	}
+	return fmt.Sprintf("%05d", highest+1), nil
+}
+
+func snakeCase(name string) string {
+	var (
+		sb        strings.Builder
+		separate  bool
+		prevLower bool
+	)
+	for _, r := range strings.TrimSpace(name) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			separate = sb.Len() > 0
+			prevLower = false
+			continue
+		}
+		if separate || (unicode.IsUpper(r) && prevLower) {
+			sb.WriteByte('_')
+		}
+		sb.WriteRune(unicode.ToLower(r))
+		separate, prevLower = false, unicode.IsLower(r) || unicode.IsDigit(r)
+	}
+	return sb.String()
+}
+
+func camelCase(snake string) string {
+	var sb strings.Builder
+	for part := range strings.SplitSeq(snake, "_") {
+		if part == "" {
+			continue
+		}
+		sb.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return sb.String()
+}
+
+func goPackageName(dir string) string {
+	if name := filepath.Base(filepath.Clean(dir)); token.IsIdentifier(name) {
+		return name
+	}
+	return "migrations"
+}
+
+const sqlMigrationSkeleton = `-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+`
+
+func goMigrationSkeleton(pkg, name string) string {
+	return fmt.Sprintf(`package %[1]s
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(up%[2]s, down%[2]s)
+}
+
+func up%[2]s(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	return nil
+}
+
+func down%[2]s(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	return nil
+}
+`, pkg, name)
+}
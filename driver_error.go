@@ -0,0 +1,182 @@
+package gooseglass
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// driverError holds the structured fields of a PostgreSQL or SQLite driver error. Drivers are
+// recognized by their method sets and field names so that none of them needs to be imported.
+type driverError struct {
+	Database   string
+	Code       string
+	Name       string
+	Message    string
+	Detail     string
+	Hint       string
+	Constraint string
+	Table      string
+	Column     string
+	// Position is the 1-based character offset into the failed statement or 0 when unknown.
+	Position int
+	// Line is the line in the migration file the Position points to or 0 when unknown.
+	Line int
+}
+
+// newDriverError returns nil when no error in the chain is recognized.
+func newDriverError(err error) *driverError {
+	var postgres interface{ SQLState() string }
+	if errors.As(err, &postgres) {
+		return newPostgresError(postgres.SQLState(), postgres)
+	}
+	var pq interface{ Get(byte) string }
+	if errors.As(err, &pq) {
+		return newPostgresError(pq.Get('C'), pq)
+	}
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if de := newSQLiteError(e); de != nil {
+			return de
+		}
+	}
+	return nil
+}
+
+func newPostgresError(code string, err any) *driverError {
+	de := &driverError{
+		Database: "PostgreSQL",
+		Code:     code,
+		Name:     postgresConditionNames[code],
+	}
+	if pq, ok := err.(interface{ Get(byte) string }); ok {
+		// github.com/lib/pq exposes the protocol fields by their single byte identifier.
+		de.Message, de.Detail, de.Hint = pq.Get('M'), pq.Get('D'), pq.Get('H')
+		de.Constraint, de.Table, de.Column = pq.Get('n'), pq.Get('t'), pq.Get('c')
+		de.Position, _ = strconv.Atoi(pq.Get('P'))
+		return de
+	}
+	v := structValue(err)
+	de.Message = stringField(v, "Message")
+	de.Detail = stringField(v, "Detail")
+	de.Hint = stringField(v, "Hint")
+	de.Constraint = stringField(v, "ConstraintName", "Constraint")
+	de.Table = stringField(v, "TableName", "Table")
+	de.Column = stringField(v, "ColumnName", "Column")
+	de.Position = int(intField(v, "Position"))
+	return de
+}
+
+// newSQLiteError recognizes modernc.org/sqlite and github.com/mattn/go-sqlite3 errors.
+func newSQLiteError(err error) *driverError {
+	t := reflect.TypeOf(err)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if !strings.Contains(t.PkgPath(), "sqlite") {
+		return nil
+	}
+	var code int64
+	if c, ok := err.(interface{ Code() int }); ok {
+		code = int64(c.Code())
+	} else if v := structValue(err); v.IsValid() {
+		code = max(intField(v, "ExtendedCode"), intField(v, "Code"))
+	}
+	if code == 0 {
+		return nil
+	}
+	name, ok := sqliteResultCodeNames[code]
+	if !ok {
+		name = sqliteResultCodeNames[code&0xff]
+	}
+	return &driverError{
+		Database: "SQLite",
+		Code:     strconv.FormatInt(code, 10),
+		Name:     name,
+		Message:  err.Error(),
+	}
+}
+
+func structValue(x any) reflect.Value {
+	v := reflect.ValueOf(x)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+	return v
+}
+
+func stringField(v reflect.Value, names ...string) string {
+	if !v.IsValid() {
+		return ""
+	}
+	for _, name := range names {
+		if f := v.FieldByName(name); f.IsValid() && f.Kind() == reflect.String {
+			return f.String()
+		}
+	}
+	return ""
+}
+
+func intField(v reflect.Value, name string) int64 {
+	if !v.IsValid() {
+		return 0
+	}
+	f := v.FieldByName(name)
+	if !f.IsValid() {
+		return 0
+	}
+	switch {
+	case f.CanInt():
+		return f.Int()
+	case f.CanUint():
+		return int64(f.Uint())
+	case f.Kind() == reflect.String:
+		n, _ := strconv.ParseInt(f.String(), 10, 64)
+		return n
+	}
+	return 0
+}
+
+var postgresConditionNames = map[string]string{
+	"23502": "not_null_violation",
+	"23503": "foreign_key_violation",
+	"23505": "unique_violation",
+	"23514": "check_violation",
+	"25P02": "in_failed_sql_transaction",
+	"40001": "serialization_failure",
+	"40P01": "deadlock_detected",
+	"42501": "insufficient_privilege",
+	"42601": "syntax_error",
+	"42701": "duplicate_column",
+	"42703": "undefined_column",
+	"42704": "undefined_object",
+	"42710": "duplicate_object",
+	"42804": "datatype_mismatch",
+	"42883": "undefined_function",
+	"42P01": "undefined_table",
+	"42P07": "duplicate_table",
+	"55P03": "lock_not_available",
+	"57014": "query_canceled",
+}
+
+var sqliteResultCodeNames = map[int64]string{
+	1:    "SQLITE_ERROR",
+	5:    "SQLITE_BUSY",
+	6:    "SQLITE_LOCKED",
+	8:    "SQLITE_READONLY",
+	13:   "SQLITE_FULL",
+	19:   "SQLITE_CONSTRAINT",
+	20:   "SQLITE_MISMATCH",
+	275:  "SQLITE_CONSTRAINT_CHECK",
+	517:  "SQLITE_BUSY_SNAPSHOT",
+	787:  "SQLITE_CONSTRAINT_FOREIGNKEY",
+	1299: "SQLITE_CONSTRAINT_NOTNULL",
+	1555: "SQLITE_CONSTRAINT_PRIMARYKEY",
+	2067: "SQLITE_CONSTRAINT_UNIQUE",
+}
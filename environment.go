@@ -0,0 +1,52 @@
+package gooseglass
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Environment names the deployment the pages migrate. The status page shows it as a banner and
+// in the title. Color is any CSS color for the banner; it defaults to red in production.
+type Environment struct {
+	Name       string
+	Color      string
+	Production bool
+}
+
+// WithEnvironment labels the pages with the environment. In production every run asks for
+// confirmation and reset is not offered.
+func WithEnvironment(env Environment) Option {
+	return func(s *server) { s.environment = &env }
+}
+
+// BannerColor is the configured Color or a default for the kind of environment.
+func (env *Environment) BannerColor() string {
+	switch {
+	case env.Color != "":
+		return env.Color
+	case env.Production:
+		return "#c62828"
+	default:
+		return "#2e7d32"
+	}
+}
+
+// CanReset reports whether the environment allows rolling every migration back.
+func (env *Environment) CanReset() bool { return env != nil && env.Name != "" && !env.Production }
+
+func (s *server) Reset(ctx context.Context, request *http.Request) (runResult, error) {
+	if !s.environment.CanReset() {
+		return runResult{}, statusError{code: http.StatusForbidden, err: errors.New("reset is only available in a non-production environment")}
+	}
+	// The reset button prompts for the environment name.
+	if got := request.Header.Get("HX-Prompt"); got != s.environment.Name {
+		return runResult{}, statusError{code: http.StatusBadRequest, err: fmt.Errorf("type %q to confirm the reset", s.environment.Name)}
+	}
+	plan := Plan{Operation: operationReset, Reason: request.FormValue("override_reason")}
+	if request.FormValue("reapply") == "true" {
+		plan.Operation = operationResetUp
+	}
+	return s.migrate(ctx, request, plan)
+}
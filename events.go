@@ -0,0 +1,79 @@
+package gooseglass
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	eventsPath            = "/events"
+	eventRefreshMigration = "refreshMigrations"
+	eventsHeartbeat       = 25 * time.Second
+)
+
+// hub fans events out to every connected browser.
+type hub struct {
+	mu      sync.Mutex
+	clients map[chan string]struct{}
+}
+
+func (h *hub) subscribe() (<-chan string, func()) {
+	c := make(chan string, 1)
+	h.mu.Lock()
+	if h.clients == nil {
+		h.clients = make(map[chan string]struct{})
+	}
+	h.clients[c] = struct{}{}
+	h.mu.Unlock()
+	return c, func() {
+		h.mu.Lock()
+		delete(h.clients, c)
+		h.mu.Unlock()
+	}
+}
+
+// broadcast sends event to every client without blocking. A client that has not read the previous
+// event misses this one, which is fine while every event means "refresh".
+func (h *hub) broadcast(event string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for c := range h.clients {
+		select {
+		case c <- event:
+		default:
+		}
+	}
+}
+
+// events streams hub events to the browser as server-sent events.
+func (s *server) events(response http.ResponseWriter, request *http.Request) {
+	// Subscribe before sending the headers so a client that sees the response has not missed any
+	// events.
+	events, unsubscribe := s.hub.subscribe()
+	defer unsubscribe()
+	rc := http.NewResponseController(response)
+	response.Header().Set("Content-Type", "text/event-stream")
+	response.Header().Set("Cache-Control", "no-cache")
+	response.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return
+	}
+	heartbeat := time.NewTicker(eventsHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		var err error
+		select {
+		case <-request.Context().Done():
+			return
+		case <-heartbeat.C:
+			_, err = fmt.Fprint(response, ": heartbeat\n\n")
+		case event := <-events:
+			_, err = fmt.Fprintf(response, "event: %s\ndata: %s\n\n", event, event)
+		}
+		if err != nil || rc.Flush() != nil {
+			return
+		}
+	}
+}
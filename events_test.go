@@ -0,0 +1,48 @@
+package gooseglass_test
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/crhntr/gooseglass"
+	"github.com/crhntr/gooseglass/internal/fake"
+)
+
+func TestEvents(t *testing.T) {
+	provider := new(fake.Provider)
+	provider.UpReturns([]*goose.MigrationResult{{
+		Source:    &goose.Source{Type: goose.TypeSQL, Path: "01_init.sql", Version: 1},
+		Direction: "up",
+	}}, nil)
+	mux := http.NewServeMux()
+	gooseglass.Pages(mux, provider)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	var streams []*bufio.Reader
+	for range 2 {
+		res, err := http.Get(srv.URL + "/events")
+		require.NoError(t, err)
+		defer func() { _ = res.Body.Close() }()
+		assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+		streams = append(streams, bufio.NewReader(res.Body))
+	}
+
+	res, err := http.Post(srv.URL+gooseglass.TemplateRoutePaths{}.Up(), "", nil)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	for _, stream := range streams {
+		line, err := stream.ReadString('\n')
+		require.NoError(t, err)
+		assert.Equal(t, "event: refreshMigrations", strings.TrimSpace(line))
+	}
+}
@@ -0,0 +1,191 @@
+package gooseglass
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	exportStatusPath = "/export/{file}"
+	exportRunPath    = "/export/runs/{file}"
+)
+
+// ErrRunNotFound is returned by RunLedger.Lookup when no run has the ID.
+var ErrRunNotFound = errors.New("run not found")
+
+// RunLedger is a Ledger that can look up a run by ID for the run downloads. MemoryLedger and
+// FileLedger implement it.
+type RunLedger interface {
+	Ledger
+	Lookup(ctx context.Context, id string) (Run, error)
+}
+
+func (l *MemoryLedger) Lookup(_ context.Context, id string) (Run, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, run := range l.runs {
+		if run.ID == id {
+			return run, nil
+		}
+	}
+	return Run{}, ErrRunNotFound
+}
+
+func (l *FileLedger) Lookup(_ context.Context, id string) (Run, error) {
+	found := Run{}
+	err := l.scan(func(run Run) bool {
+		if run.ID == id {
+			found = run
+			return false
+		}
+		return true
+	})
+	if err == nil && found.ID == "" {
+		err = ErrRunNotFound
+	}
+	return found, err
+}
+
+// splitExport splits a download file name such as "status.csv" into its name and format.
+func splitExport(file string, formats ...string) (string, string, bool) {
+	name, format, ok := strings.Cut(file, ".")
+	if !ok || name == "" {
+		return "", "", false
+	}
+	for _, f := range formats {
+		if format == f {
+			return name, format, true
+		}
+	}
+	return "", "", false
+}
+
+func (s *server) exportStatus(response http.ResponseWriter, request *http.Request) {
+	file := request.PathValue("file")
+	name, format, ok := splitExport(file, "json", "csv", "md")
+	if !ok || name != "status" {
+		http.NotFound(response, request)
+		return
+	}
+	list, err := s.provider.Status(request.Context())
+	if err != nil {
+		http.Error(response, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	report := newStatusReport(newStatusTable(list, statusQuery{}, s.allowMissing, 0), s.environment)
+	attach(response, file, format)
+	switch format {
+	case "json":
+		_ = json.NewEncoder(response).Encode(report)
+	case "csv":
+		_ = writeStatusCSV(response, report)
+	case "md":
+		_ = writeStatusMarkdown(response, report)
+	}
+}
+
+func (s *server) exportRun(response http.ResponseWriter, request *http.Request) {
+	file := request.PathValue("file")
+	id, format, ok := splitExport(file, "json", "md")
+	if !ok {
+		http.NotFound(response, request)
+		return
+	}
+	ledger, ok := s.ledger.(RunLedger)
+	if !ok {
+		http.Error(response, "the ledger cannot look up runs", http.StatusNotFound)
+		return
+	}
+	run, err := ledger.Lookup(request.Context(), id)
+	if err != nil {
+		code := http.StatusInternalServerError
+		if errors.Is(err, ErrRunNotFound) {
+			code = http.StatusNotFound
+		}
+		http.Error(response, err.Error(), code)
+		return
+	}
+	attach(response, "run-"+file, format)
+	switch format {
+	case "json":
+		_ = json.NewEncoder(response).Encode(run)
+	case "md":
+		_ = writeRunMarkdown(response, run)
+	}
+}
+
+func attach(response http.ResponseWriter, file, format string) {
+	contentType := map[string]string{
+		"json": "application/json",
+		"csv":  "text/csv; charset=utf-8",
+		"md":   "text/markdown; charset=utf-8",
+	}[format]
+	response.Header().Set("Content-Type", contentType)
+	response.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file))
+}
+
+func writeStatusCSV(w io.Writer, report StatusReport) error {
+	out := csv.NewWriter(w)
+	_ = out.Write([]string{"version", "type", "path", "state", "applied_at"})
+	for _, m := range report.Migrations {
+		_ = out.Write([]string{strconv.FormatInt(m.Version, 10), m.Type, m.Path, m.State, formatAppliedAt(m.AppliedAt)})
+	}
+	out.Flush()
+	return out.Error()
+}
+
+func writeStatusMarkdown(w io.Writer, report StatusReport) error {
+	var b strings.Builder
+	b.WriteString("# Migration status")
+	if report.Environment != "" {
+		fmt.Fprintf(&b, ": %s", report.Environment)
+	}
+	fmt.Fprintf(&b, "\n\nDatabase version: %d\n\n", report.DBVersion)
+	b.WriteString("| Version | Type | Path | State | Applied At |\n|---|---|---|---|---|\n")
+	for _, m := range report.Migrations {
+		fmt.Fprintf(&b, "| %d | %s | %s | %s | %s |\n", m.Version, m.Type, markdownCell(m.Path), m.State, formatAppliedAt(m.AppliedAt))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeRunMarkdown(w io.Writer, run Run) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Run %s: %s\n\n", run.ID, run.Operation)
+	fmt.Fprintf(&b, "- Time: %s\n", run.Time.UTC().Format(time.RFC3339))
+	if run.Actor != "" {
+		fmt.Fprintf(&b, "- Actor: %s\n", markdownCell(run.Actor))
+	}
+	if run.Approver != "" {
+		fmt.Fprintf(&b, "- Approver: %s\n", markdownCell(run.Approver))
+	}
+	if run.Reason != "" {
+		fmt.Fprintf(&b, "- Reason: %s\n", markdownCell(run.Reason))
+	}
+	b.WriteString("\n| Version | Path | Direction | Duration | Error |\n|---|---|---|---|---|\n")
+	for _, entry := range run.Entries {
+		fmt.Fprintf(&b, "| %d | %s | %s | %s | %s |\n", entry.Version, markdownCell(entry.Path), entry.Direction, entry.Duration, markdownCell(entry.Error))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func formatAppliedAt(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// markdownCell keeps free text from breaking out of a table cell.
+func markdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ", "\r", "").Replace(s)
+}
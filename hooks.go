@@ -0,0 +1,104 @@
+package gooseglass
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+// Plan describes a run about to start. Version is the target of UpTo, DownTo and ApplyMissing and
+// zero for Up and Down. Backup names the backup a restore replaces the database with. Reason is set
+// when a rollback overrides the emergency windows and Approver when a second person approved the
+// run.
+type Plan struct {
+	ID        string
+	Operation string
+	Version   int64
+	Backup    string
+	Actor     string
+	Reason    string
+	Approver  string
+}
+
+const (
+	operationUp     = "up"
+	operationUpTo   = "up-to"
+	operationDown   = "down"
+	operationDownTo = "down-to"
+	operationApply  = "apply"
+	operationRedo   = "redo"
+	// operationUpByOne and operationApplyDown back the JSON API so a remote client can implement
+	// UpByOne and ApplyVersion with direction down.
+	operationUpByOne   = "up-by-one"
+	operationApplyDown = "apply-down"
+	// operationReset rolls back every migration and operationResetUp applies them all again after.
+	operationReset   = "reset"
+	operationResetUp = "reset-up"
+	// operationRestore replaces the database with a backup.
+	operationRestore = "restore"
+)
+
+// rollsBack reports whether the plan rolls back migrations, so it needs an emergency window and
+// a backup.
+func (plan Plan) rollsBack() bool {
+	switch plan.Operation {
+	case operationDown, operationDownTo, operationRedo, operationReset, operationResetUp, operationApplyDown, operationRestore:
+		return true
+	default:
+		return false
+	}
+}
+
+// applies reports whether the plan applies migrations, so it needs a maintenance window. Redo and
+// reset-up apply the migrations they rolled back.
+func (plan Plan) applies() bool {
+	switch plan.Operation {
+	case operationUp, operationUpTo, operationApply, operationUpByOne, operationRedo, operationResetUp:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the operation as recorded in the ledger, for example "up-to 3".
+func (plan Plan) String() string {
+	switch plan.Operation {
+	case operationUp, operationUpByOne, operationDown, operationRedo, operationReset, operationResetUp:
+		return plan.Operation
+	case operationRestore:
+		return fmt.Sprintf("%s %s", plan.Operation, plan.Backup)
+	default:
+		return fmt.Sprintf("%s %d", plan.Operation, plan.Version)
+	}
+}
+
+// BeforeMigrateFunc is called before a run starts. An error aborts the run.
+type BeforeMigrateFunc func(ctx context.Context, plan Plan) error
+
+// AfterMigrateFunc is called once a run finishes with whatever it applied or rolled back and the
+// error it failed with, if any.
+type AfterMigrateFunc func(ctx context.Context, plan Plan, results []*goose.MigrationResult, err error)
+
+// WithBeforeMigrate registers a hook called before every run, in the order registered. Use it to
+// take a backup or pause background workers.
+func WithBeforeMigrate(hook BeforeMigrateFunc) Option {
+	return func(s *server) { s.beforeMigrate = append(s.beforeMigrate, hook) }
+}
+
+// WithAfterMigrate registers a hook called after every run that was not aborted by a
+// BeforeMigrateFunc, in the order registered.
+func WithAfterMigrate(hook AfterMigrateFunc) Option {
+	return func(s *server) { s.afterMigrate = append(s.afterMigrate, hook) }
+}
+
+type hookError struct {
+	plan Plan
+	err  error
+}
+
+func (e hookError) Error() string {
+	return fmt.Sprintf("%s aborted by before migrate hook: %s", e.plan, e.err)
+}
+
+func (e hookError) Unwrap() error { return e.err }
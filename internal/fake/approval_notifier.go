@@ -0,0 +1,112 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fake
+
+import (
+	"context"
+	"sync"
+
+	"github.com/crhntr/gooseglass"
+)
+
+type ApprovalNotifier struct {
+	NotifyApprovalStub        func(context.Context, gooseglass.ApprovalRequest) error
+	notifyApprovalMutex       sync.RWMutex
+	notifyApprovalArgsForCall []struct {
+		arg1 context.Context
+		arg2 gooseglass.ApprovalRequest
+	}
+	notifyApprovalReturns struct {
+		result1 error
+	}
+	notifyApprovalReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *ApprovalNotifier) NotifyApproval(arg1 context.Context, arg2 gooseglass.ApprovalRequest) error {
+	fake.notifyApprovalMutex.Lock()
+	ret, specificReturn := fake.notifyApprovalReturnsOnCall[len(fake.notifyApprovalArgsForCall)]
+	fake.notifyApprovalArgsForCall = append(fake.notifyApprovalArgsForCall, struct {
+		arg1 context.Context
+		arg2 gooseglass.ApprovalRequest
+	}{arg1, arg2})
+	stub := fake.NotifyApprovalStub
+	fakeReturns := fake.notifyApprovalReturns
+	fake.recordInvocation("NotifyApproval", []interface{}{arg1, arg2})
+	fake.notifyApprovalMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *ApprovalNotifier) NotifyApprovalCallCount() int {
+	fake.notifyApprovalMutex.RLock()
+	defer fake.notifyApprovalMutex.RUnlock()
+	return len(fake.notifyApprovalArgsForCall)
+}
+
+func (fake *ApprovalNotifier) NotifyApprovalCalls(stub func(context.Context, gooseglass.ApprovalRequest) error) {
+	fake.notifyApprovalMutex.Lock()
+	defer fake.notifyApprovalMutex.Unlock()
+	fake.NotifyApprovalStub = stub
+}
+
+func (fake *ApprovalNotifier) NotifyApprovalArgsForCall(i int) (context.Context, gooseglass.ApprovalRequest) {
+	fake.notifyApprovalMutex.RLock()
+	defer fake.notifyApprovalMutex.RUnlock()
+	argsForCall := fake.notifyApprovalArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *ApprovalNotifier) NotifyApprovalReturns(result1 error) {
+	fake.notifyApprovalMutex.Lock()
+	defer fake.notifyApprovalMutex.Unlock()
+	fake.NotifyApprovalStub = nil
+	fake.notifyApprovalReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ApprovalNotifier) NotifyApprovalReturnsOnCall(i int, result1 error) {
+	fake.notifyApprovalMutex.Lock()
+	defer fake.notifyApprovalMutex.Unlock()
+	fake.NotifyApprovalStub = nil
+	if fake.notifyApprovalReturnsOnCall == nil {
+		fake.notifyApprovalReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.notifyApprovalReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ApprovalNotifier) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *ApprovalNotifier) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ gooseglass.ApprovalNotifier = new(ApprovalNotifier)
@@ -0,0 +1,15 @@
+// Package fake holds the counterfeiter fakes the tests use.
+package fake
+
+import "github.com/crhntr/gooseglass"
+
+// gooseProvider is a Provider with the optional methods *goose.Provider also has. The Provider
+// fake is generated from it so tests can stub every run; BasicProvider has only the Provider
+// methods.
+type gooseProvider interface {
+	gooseglass.Provider
+	gooseglass.UpByOner
+	gooseglass.VersionApplier
+}
+
+var _ gooseProvider = new(Provider)
@@ -5,11 +5,25 @@ import (
 	"context"
 	"sync"
 
-	"github.com/crhntr/gooseglass"
 	goose "github.com/pressly/goose/v3"
 )
 
 type Provider struct {
+	ApplyVersionStub        func(context.Context, int64, bool) (*goose.MigrationResult, error)
+	applyVersionMutex       sync.RWMutex
+	applyVersionArgsForCall []struct {
+		arg1 context.Context
+		arg2 int64
+		arg3 bool
+	}
+	applyVersionReturns struct {
+		result1 *goose.MigrationResult
+		result2 error
+	}
+	applyVersionReturnsOnCall map[int]struct {
+		result1 *goose.MigrationResult
+		result2 error
+	}
 	DownStub        func(context.Context) (*goose.MigrationResult, error)
 	downMutex       sync.RWMutex
 	downArgsForCall []struct {
@@ -63,6 +77,19 @@ type Provider struct {
 		result1 []*goose.MigrationResult
 		result2 error
 	}
+	UpByOneStub        func(context.Context) (*goose.MigrationResult, error)
+	upByOneMutex       sync.RWMutex
+	upByOneArgsForCall []struct {
+		arg1 context.Context
+	}
+	upByOneReturns struct {
+		result1 *goose.MigrationResult
+		result2 error
+	}
+	upByOneReturnsOnCall map[int]struct {
+		result1 *goose.MigrationResult
+		result2 error
+	}
 	UpToStub        func(context.Context, int64) ([]*goose.MigrationResult, error)
 	upToMutex       sync.RWMutex
 	upToArgsForCall []struct {
@@ -81,6 +108,72 @@ type Provider struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *Provider) ApplyVersion(arg1 context.Context, arg2 int64, arg3 bool) (*goose.MigrationResult, error) {
+	fake.applyVersionMutex.Lock()
+	ret, specificReturn := fake.applyVersionReturnsOnCall[len(fake.applyVersionArgsForCall)]
+	fake.applyVersionArgsForCall = append(fake.applyVersionArgsForCall, struct {
+		arg1 context.Context
+		arg2 int64
+		arg3 bool
+	}{arg1, arg2, arg3})
+	stub := fake.ApplyVersionStub
+	fakeReturns := fake.applyVersionReturns
+	fake.recordInvocation("ApplyVersion", []interface{}{arg1, arg2, arg3})
+	fake.applyVersionMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Provider) ApplyVersionCallCount() int {
+	fake.applyVersionMutex.RLock()
+	defer fake.applyVersionMutex.RUnlock()
+	return len(fake.applyVersionArgsForCall)
+}
+
+func (fake *Provider) ApplyVersionCalls(stub func(context.Context, int64, bool) (*goose.MigrationResult, error)) {
+	fake.applyVersionMutex.Lock()
+	defer fake.applyVersionMutex.Unlock()
+	fake.ApplyVersionStub = stub
+}
+
+func (fake *Provider) ApplyVersionArgsForCall(i int) (context.Context, int64, bool) {
+	fake.applyVersionMutex.RLock()
+	defer fake.applyVersionMutex.RUnlock()
+	argsForCall := fake.applyVersionArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *Provider) ApplyVersionReturns(result1 *goose.MigrationResult, result2 error) {
+	fake.applyVersionMutex.Lock()
+	defer fake.applyVersionMutex.Unlock()
+	fake.ApplyVersionStub = nil
+	fake.applyVersionReturns = struct {
+		result1 *goose.MigrationResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Provider) ApplyVersionReturnsOnCall(i int, result1 *goose.MigrationResult, result2 error) {
+	fake.applyVersionMutex.Lock()
+	defer fake.applyVersionMutex.Unlock()
+	fake.ApplyVersionStub = nil
+	if fake.applyVersionReturnsOnCall == nil {
+		fake.applyVersionReturnsOnCall = make(map[int]struct {
+			result1 *goose.MigrationResult
+			result2 error
+		})
+	}
+	fake.applyVersionReturnsOnCall[i] = struct {
+		result1 *goose.MigrationResult
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *Provider) Down(arg1 context.Context) (*goose.MigrationResult, error) {
 	fake.downMutex.Lock()
 	ret, specificReturn := fake.downReturnsOnCall[len(fake.downArgsForCall)]
@@ -338,6 +431,70 @@ func (fake *Provider) UpReturnsOnCall(i int, result1 []*goose.MigrationResult, r
 	}{result1, result2}
 }
 
+func (fake *Provider) UpByOne(arg1 context.Context) (*goose.MigrationResult, error) {
+	fake.upByOneMutex.Lock()
+	ret, specificReturn := fake.upByOneReturnsOnCall[len(fake.upByOneArgsForCall)]
+	fake.upByOneArgsForCall = append(fake.upByOneArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.UpByOneStub
+	fakeReturns := fake.upByOneReturns
+	fake.recordInvocation("UpByOne", []interface{}{arg1})
+	fake.upByOneMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Provider) UpByOneCallCount() int {
+	fake.upByOneMutex.RLock()
+	defer fake.upByOneMutex.RUnlock()
+	return len(fake.upByOneArgsForCall)
+}
+
+func (fake *Provider) UpByOneCalls(stub func(context.Context) (*goose.MigrationResult, error)) {
+	fake.upByOneMutex.Lock()
+	defer fake.upByOneMutex.Unlock()
+	fake.UpByOneStub = stub
+}
+
+func (fake *Provider) UpByOneArgsForCall(i int) context.Context {
+	fake.upByOneMutex.RLock()
+	defer fake.upByOneMutex.RUnlock()
+	argsForCall := fake.upByOneArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Provider) UpByOneReturns(result1 *goose.MigrationResult, result2 error) {
+	fake.upByOneMutex.Lock()
+	defer fake.upByOneMutex.Unlock()
+	fake.UpByOneStub = nil
+	fake.upByOneReturns = struct {
+		result1 *goose.MigrationResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Provider) UpByOneReturnsOnCall(i int, result1 *goose.MigrationResult, result2 error) {
+	fake.upByOneMutex.Lock()
+	defer fake.upByOneMutex.Unlock()
+	fake.UpByOneStub = nil
+	if fake.upByOneReturnsOnCall == nil {
+		fake.upByOneReturnsOnCall = make(map[int]struct {
+			result1 *goose.MigrationResult
+			result2 error
+		})
+	}
+	fake.upByOneReturnsOnCall[i] = struct {
+		result1 *goose.MigrationResult
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *Provider) UpTo(arg1 context.Context, arg2 int64) ([]*goose.MigrationResult, error) {
 	fake.upToMutex.Lock()
 	ret, specificReturn := fake.upToReturnsOnCall[len(fake.upToArgsForCall)]
@@ -424,5 +581,3 @@ func (fake *Provider) recordInvocation(key string, args []interface{}) {
 	}
 	fake.invocations[key] = append(fake.invocations[key], args)
 }
-
-var _ gooseglass.Provider = new(Provider)
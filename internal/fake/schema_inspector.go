@@ -0,0 +1,115 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fake
+
+import (
+	"context"
+	"sync"
+
+	"github.com/crhntr/gooseglass"
+)
+
+type SchemaInspector struct {
+	SchemaStub        func(context.Context) (gooseglass.Schema, error)
+	schemaMutex       sync.RWMutex
+	schemaArgsForCall []struct {
+		arg1 context.Context
+	}
+	schemaReturns struct {
+		result1 gooseglass.Schema
+		result2 error
+	}
+	schemaReturnsOnCall map[int]struct {
+		result1 gooseglass.Schema
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *SchemaInspector) Schema(arg1 context.Context) (gooseglass.Schema, error) {
+	fake.schemaMutex.Lock()
+	ret, specificReturn := fake.schemaReturnsOnCall[len(fake.schemaArgsForCall)]
+	fake.schemaArgsForCall = append(fake.schemaArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.SchemaStub
+	fakeReturns := fake.schemaReturns
+	fake.recordInvocation("Schema", []interface{}{arg1})
+	fake.schemaMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *SchemaInspector) SchemaCallCount() int {
+	fake.schemaMutex.RLock()
+	defer fake.schemaMutex.RUnlock()
+	return len(fake.schemaArgsForCall)
+}
+
+func (fake *SchemaInspector) SchemaCalls(stub func(context.Context) (gooseglass.Schema, error)) {
+	fake.schemaMutex.Lock()
+	defer fake.schemaMutex.Unlock()
+	fake.SchemaStub = stub
+}
+
+func (fake *SchemaInspector) SchemaArgsForCall(i int) context.Context {
+	fake.schemaMutex.RLock()
+	defer fake.schemaMutex.RUnlock()
+	argsForCall := fake.schemaArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *SchemaInspector) SchemaReturns(result1 gooseglass.Schema, result2 error) {
+	fake.schemaMutex.Lock()
+	defer fake.schemaMutex.Unlock()
+	fake.SchemaStub = nil
+	fake.schemaReturns = struct {
+		result1 gooseglass.Schema
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SchemaInspector) SchemaReturnsOnCall(i int, result1 gooseglass.Schema, result2 error) {
+	fake.schemaMutex.Lock()
+	defer fake.schemaMutex.Unlock()
+	fake.SchemaStub = nil
+	if fake.schemaReturnsOnCall == nil {
+		fake.schemaReturnsOnCall = make(map[int]struct {
+			result1 gooseglass.Schema
+			result2 error
+		})
+	}
+	fake.schemaReturnsOnCall[i] = struct {
+		result1 gooseglass.Schema
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SchemaInspector) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *SchemaInspector) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ gooseglass.SchemaInspector = new(SchemaInspector)
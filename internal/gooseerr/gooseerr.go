@@ -0,0 +1,42 @@
+// Package gooseerr names the goose errors the JSON API reports in the goose_error field, so the
+// client package can return the same errors a local provider would.
+package gooseerr
+
+import (
+	"errors"
+
+	"github.com/pressly/goose/v3"
+)
+
+// known is matched in order and the first match wins, so keep the order fixed.
+var known = []struct {
+	name string
+	err  error
+}{
+	{"no_current_version", goose.ErrNoCurrentVersion},
+	{"no_next_version", goose.ErrNoNextVersion},
+	{"version_not_found", goose.ErrVersionNotFound},
+	{"no_migrations", goose.ErrNoMigrations},
+	{"already_applied", goose.ErrAlreadyApplied},
+	{"not_applied", goose.ErrNotApplied},
+}
+
+// Name returns the name of the first goose error err matches, or "" if it matches none.
+func Name(err error) string {
+	for _, k := range known {
+		if errors.Is(err, k.err) {
+			return k.name
+		}
+	}
+	return ""
+}
+
+// ByName returns the goose error reported as name, or nil if the name is unknown.
+func ByName(name string) error {
+	for _, k := range known {
+		if k.name == name {
+			return k.err
+		}
+	}
+	return nil
+}
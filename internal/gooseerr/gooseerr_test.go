@@ -0,0 +1,18 @@
+package gooseerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/crhntr/gooseglass/internal/gooseerr"
+)
+
+func TestName(t *testing.T) {
+	assert.Equal(t, goose.ErrNotApplied, gooseerr.ByName(gooseerr.Name(goose.ErrNotApplied)))
+	assert.Equal(t, "version_not_found", gooseerr.Name(errors.Join(goose.ErrNotApplied, goose.ErrVersionNotFound)), "first match in a fixed order")
+	assert.Empty(t, gooseerr.Name(errors.New("boom")))
+	assert.Nil(t, gooseerr.ByName("unknown"))
+}
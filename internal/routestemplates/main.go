@@ -0,0 +1,43 @@
+// Command routestemplates adds a templates parameter to the routes function muxt generates, so each
+// Pages call renders its routes from its own template set. The parameter shadows the package
+// templates variable, which muxt still checks the route templates against.
+//
+// Run it from go generate after muxt.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+)
+
+const (
+	file      = "template_routes.go"
+	signature = "func routes(mux *http.ServeMux, receiver routesReceiver) TemplateRoutePaths {"
+	threaded  = "func routes(mux *http.ServeMux, receiver routesReceiver, templates *template.Template) TemplateRoutePaths {"
+)
+
+func main() {
+	if err := run(); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "routestemplates:", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	src, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	if n := bytes.Count(src, []byte(signature)); n != 1 {
+		return fmt.Errorf("%s: found %d routes functions to thread, want 1", file, n)
+	}
+	src = bytes.Replace(src, []byte(signature), []byte(threaded), 1)
+	src = bytes.Replace(src, []byte("import (\n"), []byte("import (\n\t\"html/template\"\n"), 1)
+	src, err = format.Source(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, src, 0o644)
+}
@@ -0,0 +1,131 @@
+package sqlitetest_test
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	"github.com/crhntr/gooseglass"
+)
+
+func TestSQLiteBackups(t *testing.T) {
+	dir := t.TempDir()
+	db, err := sql.Open("sqlite", filepath.Join(dir, "app.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	for _, statement := range []string{
+		`CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, email TEXT NOT NULL)`,
+		`CREATE UNIQUE INDEX users_email ON users (email)`,
+		`CREATE TABLE posts (id INTEGER PRIMARY KEY, author_id INTEGER REFERENCES users (id), title TEXT)`,
+		`CREATE VIEW authors AS SELECT DISTINCT users.email FROM users JOIN posts ON posts.author_id = users.id`,
+		`CREATE TRIGGER users_lower AFTER INSERT ON users BEGIN UPDATE users SET email = lower(NEW.email) WHERE id = NEW.id; END`,
+		`INSERT INTO users (email) VALUES ('Ada@example.com'), ('grace@example.com')`,
+		`INSERT INTO posts (id, author_id, title) VALUES (1, 1, 'Notes')`,
+	} {
+		_, err := db.Exec(statement)
+		require.NoError(t, err, statement)
+	}
+	before := sqliteContents(t, db)
+
+	backups := gooseglass.NewSQLiteBackups(db, filepath.Join(dir, "backups"))
+	backup, err := backups.Backup(t.Context())
+	require.NoError(t, err)
+	assert.Positive(t, backup.Size)
+	list, err := backups.List(t.Context())
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, backup.Name, list[0].Name)
+
+	for _, statement := range []string{
+		`INSERT INTO users (email) VALUES ('linus@example.com')`,
+		`DELETE FROM posts`,
+		`DROP VIEW authors`,
+		`CREATE TABLE comments (id INTEGER PRIMARY KEY)`,
+	} {
+		_, err := db.Exec(statement)
+		require.NoError(t, err, statement)
+	}
+	changed := sqliteContents(t, db)
+	require.NotEqual(t, before, changed)
+
+	t.Run("restore", func(t *testing.T) {
+		require.NoError(t, backups.Restore(t.Context(), backup.Name))
+		assert.Equal(t, before, sqliteContents(t, db))
+
+		_, err := db.Exec(`INSERT INTO users (email) VALUES ('Margaret@example.com')`)
+		require.NoError(t, err)
+		var id int64
+		var email string
+		require.NoError(t, db.QueryRow(`SELECT id, email FROM users WHERE email LIKE 'margaret%'`).Scan(&id, &email))
+		assert.Equal(t, int64(3), id, "the autoincrement sequence is restored")
+		assert.Equal(t, "margaret@example.com", email, "triggers are restored")
+	})
+
+	t.Run("failed restore leaves the database unchanged", func(t *testing.T) {
+		broken, err := backups.Backup(t.Context())
+		require.NoError(t, err)
+		current := sqliteContents(t, db)
+
+		// Make the saved unique index cover a constant so creating it fails after the current
+		// tables were dropped and the saved rows copied.
+		file, err := sql.Open("sqlite", filepath.Join(dir, "backups", broken.Name))
+		require.NoError(t, err)
+		for _, statement := range []string{
+			`PRAGMA writable_schema = ON`,
+			`UPDATE sqlite_master SET sql = 'CREATE UNIQUE INDEX users_email ON users (substr(email, 1, 0))' WHERE name = 'users_email'`,
+		} {
+			_, err := file.Exec(statement)
+			require.NoError(t, err, statement)
+		}
+		require.NoError(t, file.Close())
+
+		assert.ErrorContains(t, backups.Restore(t.Context(), broken.Name), "UNIQUE constraint failed")
+		assert.Equal(t, current, sqliteContents(t, db))
+	})
+
+	t.Run("rejects names that are not backups", func(t *testing.T) {
+		assert.Error(t, backups.Restore(t.Context(), "../app.db"))
+		assert.Error(t, backups.Restore(t.Context(), "app.db"))
+	})
+}
+
+// sqliteContents returns the schema of the database followed by the rows of every table.
+func sqliteContents(t *testing.T, db *sql.DB) []string {
+	t.Helper()
+	var contents, tables []string
+	rows, err := db.Query(`SELECT type, name, coalesce(sql, '') FROM sqlite_master WHERE name NOT LIKE 'sqlite_%' ORDER BY name`)
+	require.NoError(t, err)
+	for rows.Next() {
+		var kind, name, statement string
+		require.NoError(t, rows.Scan(&kind, &name, &statement))
+		contents = append(contents, statement)
+		if kind == "table" {
+			tables = append(tables, name)
+		}
+	}
+	require.NoError(t, rows.Err())
+	require.NoError(t, rows.Close())
+	for _, table := range tables {
+		rows, err := db.Query(`SELECT * FROM "` + table + `" ORDER BY 1`)
+		require.NoError(t, err)
+		columns, err := rows.Columns()
+		require.NoError(t, err)
+		for rows.Next() {
+			values := make([]any, len(columns))
+			pointers := make([]any, len(columns))
+			for i := range values {
+				pointers[i] = &values[i]
+			}
+			require.NoError(t, rows.Scan(pointers...))
+			contents = append(contents, table+": "+fmt.Sprint(values...))
+		}
+		require.NoError(t, rows.Err())
+		require.NoError(t, rows.Close())
+	}
+	return contents
+}
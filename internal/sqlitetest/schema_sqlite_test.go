@@ -0,0 +1,111 @@
+package sqlitetest_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/typelate/dom/domtest"
+	_ "modernc.org/sqlite"
+
+	"github.com/crhntr/gooseglass"
+	"github.com/crhntr/gooseglass/internal/fake"
+)
+
+func TestSQLiteInspector(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "app.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	for _, statement := range []string{
+		`CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT NOT NULL DEFAULT '', nick TEXT)`,
+		`CREATE UNIQUE INDEX users_email ON users (email)`,
+		`CREATE TABLE posts (id INTEGER PRIMARY KEY, author_id INTEGER NOT NULL REFERENCES users (id), title TEXT)`,
+		`CREATE INDEX posts_author_title ON posts (author_id, title)`,
+		`CREATE VIEW authors AS SELECT DISTINCT users.email FROM users JOIN posts ON posts.author_id = users.id`,
+	} {
+		_, err := db.Exec(statement)
+		require.NoError(t, err, statement)
+	}
+	inspector := gooseglass.NewSQLiteInspector(db)
+
+	t.Run("schema", func(t *testing.T) {
+		schema, err := inspector.Schema(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, gooseglass.Schema{Tables: []gooseglass.Table{
+			{
+				Name: "posts",
+				Columns: []gooseglass.Column{
+					{Name: "id", Type: "INTEGER"},
+					{Name: "author_id", Type: "INTEGER"},
+					{Name: "title", Type: "TEXT", Nullable: true},
+				},
+				Indexes: []gooseglass.Index{
+					{Name: "posts_author_title", Columns: []string{"author_id", "title"}},
+				},
+				ForeignKeys: []gooseglass.ForeignKey{
+					{Columns: []string{"author_id"}, RefTable: "users", RefColumns: []string{"id"}},
+				},
+			},
+			{
+				Name: "users",
+				Columns: []gooseglass.Column{
+					{Name: "id", Type: "INTEGER"},
+					{Name: "email", Type: "TEXT", Default: "''"},
+					{Name: "nick", Type: "TEXT", Nullable: true},
+				},
+				Indexes: []gooseglass.Index{
+					{Name: "users_email", Columns: []string{"email"}, Unique: true},
+				},
+			},
+		}}, schema)
+	})
+
+	t.Run("up shows the schema changes it made", func(t *testing.T) {
+		provider := new(fake.Provider)
+		provider.UpStub = func(ctx context.Context) ([]*goose.MigrationResult, error) {
+			for _, statement := range []string{
+				`ALTER TABLE users ADD COLUMN name TEXT`,
+				`DROP INDEX posts_author_title`,
+				`CREATE TABLE comments (id INTEGER PRIMARY KEY, post_id INTEGER REFERENCES posts (id))`,
+			} {
+				if _, err := db.ExecContext(ctx, statement); err != nil {
+					return nil, err
+				}
+			}
+			return []*goose.MigrationResult{{
+				Source:    &goose.Source{Type: goose.TypeSQL, Path: "02_comments.sql", Version: 2},
+				Direction: "up",
+			}}, nil
+		}
+		mux := http.NewServeMux()
+		gooseglass.Pages(mux, provider, gooseglass.WithSchemaInspector(inspector))
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, gooseglass.TemplateRoutePaths{}.Up(), nil))
+		resp := rec.Result()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		document := domtest.ParseResponseDocument(t, resp)
+
+		comments := document.QuerySelector(`.schema-diff details[data-change="added"]`)
+		require.NotNil(t, comments)
+		assert.Contains(t, comments.QuerySelector(`summary`).TextContent(), "comments")
+
+		changed := document.QuerySelectorAll(`.schema-diff details[data-change="changed"]`)
+		require.Equal(t, 2, changed.Length())
+		posts, users := changed.Item(0), changed.Item(1)
+		assert.Contains(t, posts.QuerySelector(`summary`).TextContent(), "posts")
+		removed := posts.QuerySelector(`tbody tr[data-change="removed"]`)
+		require.NotNil(t, removed)
+		assert.Contains(t, removed.TextContent(), "posts_author_title")
+		assert.Contains(t, users.QuerySelector(`summary`).TextContent(), "users")
+		added := users.QuerySelector(`tbody tr[data-change="added"]`)
+		require.NotNil(t, added)
+		assert.Contains(t, added.TextContent(), "name")
+	})
+}
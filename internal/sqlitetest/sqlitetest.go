@@ -0,0 +1,3 @@
+// Package sqlitetest tests the SQLite backups and schema inspector against modernc.org/sqlite. It
+// is a separate module so the driver is not a dependency of the gooseglass module.
+package sqlitetest
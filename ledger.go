@@ -0,0 +1,159 @@
+package gooseglass
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Ledger stores every run triggered from the pages.
+type Ledger interface {
+	Record(ctx context.Context, run Run) error
+	History(ctx context.Context, version int64) ([]LedgerEntry, error)
+}
+
+// Run is one operation triggered from the pages, such as Up or DownTo.
+type Run struct {
+	ID           string        `json:"id"`
+	Operation    string        `json:"operation"`
+	Actor        string        `json:"actor,omitempty"`
+	Reason       string        `json:"reason,omitempty"`
+	Approver     string        `json:"approver,omitempty"`
+	Time         time.Time     `json:"time"`
+	Entries      []LedgerEntry `json:"entries"`
+	SchemaBefore *Schema       `json:"schema_before,omitempty"`
+	SchemaAfter  *Schema       `json:"schema_after,omitempty"`
+}
+
+// LedgerEntry is one migration applied or rolled back by a run. History copies Actor, Reason and
+// Time from the run.
+type LedgerEntry struct {
+	Version   int64         `json:"version"`
+	Path      string        `json:"path,omitempty"`
+	Direction string        `json:"direction"`
+	Duration  time.Duration `json:"duration"`
+	Error     string        `json:"error,omitempty"`
+	Actor     string        `json:"-"`
+	Reason    string        `json:"-"`
+	Time      time.Time     `json:"-"`
+}
+
+// defaultMemoryLedgerLimit is how many runs a MemoryLedger keeps when no limit is given.
+const defaultMemoryLedgerLimit = 100
+
+// MemoryLedger keeps the latest runs, with their schema snapshots, until the process exits. It is
+// the default Ledger, keeping the last 100 runs; use a FileLedger to keep them all.
+type MemoryLedger struct {
+	mu    sync.Mutex
+	limit int
+	runs  []Run
+	// next is where the next run goes once runs holds limit runs, which is also the oldest run.
+	next int
+}
+
+// NewMemoryLedger keeps the last limit runs, or the last 100 when limit is zero or less.
+func NewMemoryLedger(limit int) *MemoryLedger {
+	if limit <= 0 {
+		limit = defaultMemoryLedgerLimit
+	}
+	return &MemoryLedger{limit: limit}
+}
+
+func (l *MemoryLedger) Record(_ context.Context, run Run) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.runs) < l.limit {
+		l.runs = append(l.runs, run)
+		return nil
+	}
+	l.runs[l.next] = run
+	l.next = (l.next + 1) % l.limit
+	return nil
+}
+
+func (l *MemoryLedger) History(_ context.Context, version int64) ([]LedgerEntry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var list []LedgerEntry
+	for _, run := range l.ordered() {
+		list = run.appendHistory(list, version)
+	}
+	return list, nil
+}
+
+// ordered returns the runs oldest first. The caller holds mu.
+func (l *MemoryLedger) ordered() []Run {
+	return append(slices.Clone(l.runs[l.next:]), l.runs[:l.next]...)
+}
+
+// FileLedger appends runs to a JSON lines file.
+type FileLedger struct {
+	mu   sync.Mutex
+	name string
+}
+
+func NewFileLedger(name string) *FileLedger { return &FileLedger{name: name} }
+
+func (l *FileLedger) Record(_ context.Context, run Run) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	f, err := os.OpenFile(l.name, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(f).Encode(run); err != nil {
+		return errors.Join(err, f.Close())
+	}
+	return f.Close()
+}
+
+func (l *FileLedger) History(_ context.Context, version int64) ([]LedgerEntry, error) {
+	var list []LedgerEntry
+	err := l.scan(func(run Run) bool {
+		list = run.appendHistory(list, version)
+		return true
+	})
+	return list, err
+}
+
+// scan calls yield with each run in the order they were recorded until it returns false.
+func (l *FileLedger) scan(yield func(Run) bool) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	f, err := os.Open(l.name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 16*1024*1024)
+	for scanner.Scan() {
+		var run Run
+		if err := json.Unmarshal(scanner.Bytes(), &run); err != nil {
+			return err
+		}
+		if !yield(run) {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+func (run Run) appendHistory(list []LedgerEntry, version int64) []LedgerEntry {
+	for _, entry := range run.Entries {
+		if entry.Version == version {
+			entry.Actor, entry.Reason, entry.Time = run.Actor, run.Reason, run.Time
+			list = append(list, entry)
+		}
+	}
+	return list
+}
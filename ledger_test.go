@@ -0,0 +1,75 @@
+package gooseglass_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/crhntr/gooseglass"
+)
+
+func TestFileLedger(t *testing.T) {
+	ledger := gooseglass.NewFileLedger(filepath.Join(t.TempDir(), "runs.jsonl"))
+
+	history, err := ledger.History(t.Context(), 1)
+	require.NoError(t, err)
+	assert.Empty(t, history)
+
+	at := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, ledger.Record(t.Context(), gooseglass.Run{
+		ID: "a", Operation: "up", Actor: "ada", Time: at,
+		Entries: []gooseglass.LedgerEntry{
+			{Version: 1, Direction: "up", Duration: time.Second},
+			{Version: 2, Direction: "up", Duration: time.Second, Error: "boom"},
+		},
+		SchemaAfter: &gooseglass.Schema{Tables: []gooseglass.Table{{Name: "users"}}},
+	}))
+	require.NoError(t, ledger.Record(t.Context(), gooseglass.Run{
+		ID: "b", Operation: "down", Actor: "grace", Time: at.Add(time.Hour),
+		Entries: []gooseglass.LedgerEntry{
+			{Version: 1, Direction: "down", Duration: time.Millisecond},
+		},
+	}))
+
+	history, err = ledger.History(t.Context(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, []gooseglass.LedgerEntry{
+		{Version: 1, Direction: "up", Duration: time.Second, Actor: "ada", Time: at},
+		{Version: 1, Direction: "down", Duration: time.Millisecond, Actor: "grace", Time: at.Add(time.Hour)},
+	}, history)
+
+	run, err := ledger.Lookup(t.Context(), "b")
+	require.NoError(t, err)
+	assert.Equal(t, "grace", run.Actor)
+	_, err = ledger.Lookup(t.Context(), "c")
+	assert.ErrorIs(t, err, gooseglass.ErrRunNotFound)
+}
+
+func TestMemoryLedger(t *testing.T) {
+	ledger := gooseglass.NewMemoryLedger(2)
+
+	at := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i, id := range []string{"a", "b", "c"} {
+		require.NoError(t, ledger.Record(t.Context(), gooseglass.Run{
+			ID: id, Operation: "up", Actor: "ada", Time: at.Add(time.Duration(i) * time.Hour),
+			Entries:     []gooseglass.LedgerEntry{{Version: 1, Direction: "up", Duration: time.Duration(i+1) * time.Second}},
+			SchemaAfter: &gooseglass.Schema{Tables: []gooseglass.Table{{Name: "users"}}},
+		}))
+	}
+
+	history, err := ledger.History(t.Context(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, []gooseglass.LedgerEntry{
+		{Version: 1, Direction: "up", Duration: 2 * time.Second, Actor: "ada", Time: at.Add(time.Hour)},
+		{Version: 1, Direction: "up", Duration: 3 * time.Second, Actor: "ada", Time: at.Add(2 * time.Hour)},
+	}, history, "only the last two runs, oldest first")
+
+	_, err = ledger.Lookup(t.Context(), "a")
+	assert.ErrorIs(t, err, gooseglass.ErrRunNotFound)
+	run, err := ledger.Lookup(t.Context(), "c")
+	require.NoError(t, err)
+	assert.NotNil(t, run.SchemaAfter)
+}
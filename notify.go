@@ -0,0 +1,267 @@
+package gooseglass
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pressly/goose/v3"
+)
+
+const (
+	EventStarted         = "started"
+	EventSucceeded       = "succeeded"
+	EventPartiallyFailed = "partially_failed"
+	EventFailed          = "failed"
+)
+
+// Event is sent to notifiers when a run starts and again when it finishes.
+type Event struct {
+	Kind      string        `json:"kind"`
+	RunID     string        `json:"run_id"`
+	Operation string        `json:"operation"`
+	Actor     string        `json:"actor,omitempty"`
+	Approver  string        `json:"approver,omitempty"`
+	Reason    string        `json:"reason,omitempty"`
+	Time      time.Time     `json:"time"`
+	Entries   []LedgerEntry `json:"entries,omitempty"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// Notifier is told about every run. Notify is called from a queue shared by the notifiers of a
+// Pages call, one event at a time in the order they happened, so a slow notifier delays later
+// notifications but never a run. Errors are logged.
+type Notifier interface {
+	Notify(ctx context.Context, event Event) error
+}
+
+// WithNotifier adds a notifier. It may be used more than once.
+func WithNotifier(notifier Notifier) Option {
+	return func(s *server) { s.notifiers = append(s.notifiers, notifier) }
+}
+
+func newEvent(plan Plan, now time.Time) Event {
+	return Event{
+		Kind:      EventStarted,
+		RunID:     plan.ID,
+		Operation: plan.String(),
+		Actor:     plan.Actor,
+		Approver:  plan.Approver,
+		Reason:    plan.Reason,
+		Time:      now,
+	}
+}
+
+func (event Event) finished(entries []LedgerEntry, err error, now time.Time) Event {
+	event.Kind, event.Entries, event.Time = EventSucceeded, entries, now
+	if err == nil {
+		return event
+	}
+	event.Kind, event.Error = EventFailed, err.Error()
+	var partial *goose.PartialError
+	if errors.As(err, &partial) && len(partial.Applied) > 0 {
+		event.Kind = EventPartiallyFailed
+	}
+	return event
+}
+
+// notifications sends queued notifications in order on one goroutine, so slow notifiers do not
+// hold up runs.
+type notifications struct {
+	mu      sync.Mutex
+	pending []func()
+	sending bool
+}
+
+// enqueue queues send and starts sending the queue if it is not already being sent.
+func (q *notifications) enqueue(send func()) {
+	q.mu.Lock()
+	q.pending = append(q.pending, send)
+	start := !q.sending
+	q.sending = true
+	q.mu.Unlock()
+	if start {
+		go q.send()
+	}
+}
+
+// send runs queued notifications until the queue is empty.
+func (q *notifications) send() {
+	for {
+		q.mu.Lock()
+		if len(q.pending) == 0 {
+			q.sending = false
+			q.mu.Unlock()
+			return
+		}
+		next := q.pending[0]
+		q.pending = q.pending[1:]
+		q.mu.Unlock()
+		next()
+	}
+}
+
+// notify queues the event for the notifiers.
+func (s *server) notify(ctx context.Context, event Event) {
+	if len(s.notifiers) == 0 {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	s.notifications.enqueue(func() {
+		for _, notifier := range s.notifiers {
+			if err := notifier.Notify(ctx, event); err != nil {
+				slog.ErrorContext(ctx, "failed to send notification", slog.String("run", event.RunID), slog.String("event", event.Kind), slog.String("error", err.Error()))
+			}
+		}
+	})
+}
+
+const (
+	webhookEventHeader     = "X-Gooseglass-Event"
+	webhookTimestampHeader = "X-Gooseglass-Timestamp"
+	webhookSignatureHeader = "X-Gooseglass-Signature"
+)
+
+// WebhookNotifier posts events to a URL. When it has a secret each request is signed with
+// HMAC-SHA256 over the timestamp header, a period, and the body; see VerifyWebhookSignature.
+// Requests failing with a network error, 429 or 5xx are retried.
+type WebhookNotifier struct {
+	url     string
+	secret  []byte
+	payload func(Event) any
+
+	// Client sends the requests. It defaults to a client with a 10 second timeout.
+	Client *http.Client
+	// Attempts is how many times a request is tried, three by default.
+	Attempts int
+	// Backoff is the wait before the second attempt and doubles after each retry.
+	Backoff time.Duration
+}
+
+// NewWebhookNotifier posts each Event as JSON.
+func NewWebhookNotifier(url string, secret []byte) *WebhookNotifier {
+	return newWebhookNotifier(url, secret, func(event Event) any { return event })
+}
+
+// NewSlackNotifier posts a message to a Slack (or compatible) incoming webhook.
+func NewSlackNotifier(url string, secret []byte) *WebhookNotifier {
+	return newWebhookNotifier(url, secret, func(event Event) any {
+		return struct {
+			Text string `json:"text"`
+		}{Text: event.Summary()}
+	})
+}
+
+func newWebhookNotifier(url string, secret []byte, payload func(Event) any) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:      url,
+		secret:   secret,
+		payload:  payload,
+		Client:   &http.Client{Timeout: 10 * time.Second},
+		Attempts: 3,
+		Backoff:  500 * time.Millisecond,
+	}
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, event Event) error {
+	body, err := json.Marshal(n.payload(event))
+	if err != nil {
+		return err
+	}
+	backoff := n.Backoff
+	for attempt := 1; ; attempt++ {
+		retry, err := n.post(ctx, event, body)
+		if err == nil || !retry || attempt >= n.Attempts {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (n *WebhookNotifier) post(ctx context.Context, event Event, body []byte) (retry bool, _ error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookEventHeader, event.Kind)
+	if len(n.secret) > 0 {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(webhookTimestampHeader, timestamp)
+		req.Header.Set(webhookSignatureHeader, signWebhook(n.secret, timestamp, body))
+	}
+	res, err := n.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer func() { _ = res.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 1<<16))
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return false, nil
+	}
+	retry = res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500
+	return retry, fmt.Errorf("webhook %s responded %s", n.url, res.Status)
+}
+
+func signWebhook(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature reports whether a request body was signed by a WebhookNotifier with
+// secret, given its X-Gooseglass-Timestamp and X-Gooseglass-Signature headers. Timestamps more than
+// tolerance from now are rejected so a captured request can not be replayed later; 5 minutes
+// allows for clock skew and retries.
+func VerifyWebhookSignature(secret []byte, timestamp, signature string, body []byte, tolerance time.Duration) bool {
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := time.Since(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return false
+	}
+	return hmac.Equal([]byte(signWebhook(secret, timestamp, body)), []byte(signature))
+}
+
+// Summary is a one line description of the event, used as the Slack message.
+func (event Event) Summary() string {
+	var sb strings.Builder
+	sb.WriteString(event.Operation)
+	if event.Actor != "" {
+		fmt.Fprintf(&sb, " by %s", event.Actor)
+	}
+	if event.Approver != "" {
+		fmt.Fprintf(&sb, " (approved by %s)", event.Approver)
+	}
+	switch event.Kind {
+	case EventStarted:
+		sb.WriteString(" started")
+	case EventSucceeded:
+		fmt.Fprintf(&sb, " succeeded: %d migrations", len(event.Entries))
+	case EventPartiallyFailed:
+		fmt.Fprintf(&sb, " partially failed after %d migrations: %s", len(event.Entries)-1, event.Error)
+	case EventFailed:
+		fmt.Fprintf(&sb, " failed: %s", event.Error)
+	}
+	return sb.String()
+}
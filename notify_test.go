@@ -0,0 +1,122 @@
+package gooseglass_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/crhntr/gooseglass"
+)
+
+func TestWebhookNotifier(t *testing.T) {
+	secret := []byte("hunter2")
+	event := gooseglass.Event{
+		Kind:      gooseglass.EventSucceeded,
+		RunID:     "RUN1",
+		Operation: "up-to 3",
+		Actor:     "ada",
+		Entries:   []gooseglass.LedgerEntry{{Version: 3, Path: "03_add_index.sql", Direction: "up"}},
+	}
+
+	t.Run("signed JSON payload", func(t *testing.T) {
+		var received gooseglass.Event
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.Equal(t, gooseglass.EventSucceeded, r.Header.Get("X-Gooseglass-Event"))
+			assert.True(t, gooseglass.VerifyWebhookSignature(secret, r.Header.Get("X-Gooseglass-Timestamp"), r.Header.Get("X-Gooseglass-Signature"), body, 5*time.Minute))
+			assert.False(t, gooseglass.VerifyWebhookSignature([]byte("wrong"), r.Header.Get("X-Gooseglass-Timestamp"), r.Header.Get("X-Gooseglass-Signature"), body, 5*time.Minute))
+			require.NoError(t, json.Unmarshal(body, &received))
+		}))
+		defer srv.Close()
+
+		require.NoError(t, gooseglass.NewWebhookNotifier(srv.URL, secret).Notify(t.Context(), event))
+		assert.Equal(t, "RUN1", received.RunID)
+		assert.Equal(t, "up-to 3", received.Operation)
+		require.Len(t, received.Entries, 1)
+	})
+
+	t.Run("replayed payload", func(t *testing.T) {
+		var timestamp, signature string
+		var body []byte
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			timestamp, signature = r.Header.Get("X-Gooseglass-Timestamp"), r.Header.Get("X-Gooseglass-Signature")
+			body, _ = io.ReadAll(r.Body)
+		}))
+		defer srv.Close()
+
+		require.NoError(t, gooseglass.NewWebhookNotifier(srv.URL, secret).Notify(t.Context(), event))
+		sent, err := strconv.ParseInt(timestamp, 10, 64)
+		require.NoError(t, err)
+		age := time.Since(time.Unix(sent, 0))
+		assert.True(t, gooseglass.VerifyWebhookSignature(secret, timestamp, signature, body, age+time.Minute))
+		assert.False(t, gooseglass.VerifyWebhookSignature(secret, timestamp, signature, body, 0), "older than the tolerance")
+		assert.False(t, gooseglass.VerifyWebhookSignature(secret, "not a time", signature, body, time.Hour))
+	})
+
+	t.Run("slack payload", func(t *testing.T) {
+		var received struct {
+			Text string `json:"text"`
+		}
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Empty(t, r.Header.Get("X-Gooseglass-Signature"))
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		}))
+		defer srv.Close()
+
+		require.NoError(t, gooseglass.NewSlackNotifier(srv.URL, nil).Notify(t.Context(), event))
+		assert.Equal(t, "up-to 3 by ada succeeded: 1 migrations", received.Text)
+	})
+
+	t.Run("retries server errors", func(t *testing.T) {
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+			}
+		}))
+		defer srv.Close()
+
+		notifier := gooseglass.NewWebhookNotifier(srv.URL, secret)
+		notifier.Backoff = time.Millisecond
+		require.NoError(t, notifier.Notify(t.Context(), event))
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer srv.Close()
+
+		notifier := gooseglass.NewWebhookNotifier(srv.URL, secret)
+		notifier.Attempts, notifier.Backoff = 2, time.Millisecond
+		assert.ErrorContains(t, notifier.Notify(t.Context(), event), "503")
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer srv.Close()
+
+		notifier := gooseglass.NewWebhookNotifier(srv.URL, secret)
+		notifier.Backoff = time.Millisecond
+		assert.Error(t, notifier.Notify(t.Context(), event))
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
@@ -0,0 +1,163 @@
+package gooseglass
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pressly/goose/v3"
+)
+
+// ReloadingProvider rebuilds a Provider whenever the files in a migrations directory change, for
+// use in development. Pass it to Pages in place of the provider so every open status page
+// refreshes after a reload.
+//
+// Go migrations are compiled into the program, so changes to .go files are ignored; rebuild and
+// restart the program to pick them up.
+//
+// Replaced providers are not closed because closing a *goose.Provider closes its database.
+type ReloadingProvider struct {
+	dir   string
+	build func() (Provider, error)
+
+	mu          sync.RWMutex
+	current     Provider
+	fingerprint string
+	listeners   []func()
+}
+
+// NewReloadingProvider calls build once now and again after every change to dir.
+func NewReloadingProvider(dir string, build func() (Provider, error)) (*ReloadingProvider, error) {
+	fingerprint, err := dirFingerprint(dir)
+	if err != nil {
+		return nil, err
+	}
+	current, err := build()
+	if err != nil {
+		return nil, err
+	}
+	return &ReloadingProvider{dir: dir, build: build, current: current, fingerprint: fingerprint}, nil
+}
+
+// Watch checks the directory every interval until ctx is cancelled. Build failures are logged and
+// the previous provider is kept. interval must be positive.
+func (p *ReloadingProvider) Watch(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("reload interval must be positive, got %s", interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		if _, err := p.Reload(); err != nil {
+			slog.ErrorContext(ctx, "failed to reload migrations", slog.String("dir", p.dir), slog.String("error", err.Error()))
+		}
+	}
+}
+
+// Reload rebuilds the provider if the directory changed since the last build and reports whether
+// it did.
+func (p *ReloadingProvider) Reload() (bool, error) {
+	fingerprint, err := dirFingerprint(p.dir)
+	if err != nil {
+		return false, err
+	}
+	p.mu.RLock()
+	unchanged := fingerprint == p.fingerprint
+	p.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+	next, err := p.build()
+	if err != nil {
+		return false, err
+	}
+	p.mu.Lock()
+	p.current, p.fingerprint = next, fingerprint
+	listeners := p.listeners
+	p.mu.Unlock()
+	for _, f := range listeners {
+		f()
+	}
+	return true, nil
+}
+
+func (p *ReloadingProvider) onReload(f func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.listeners = append(p.listeners, f)
+}
+
+func (p *ReloadingProvider) provider() Provider {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.current
+}
+
+func (p *ReloadingProvider) Status(ctx context.Context) ([]*goose.MigrationStatus, error) {
+	return p.provider().Status(ctx)
+}
+
+func (p *ReloadingProvider) Down(ctx context.Context) (*goose.MigrationResult, error) {
+	return p.provider().Down(ctx)
+}
+
+func (p *ReloadingProvider) DownTo(ctx context.Context, version int64) ([]*goose.MigrationResult, error) {
+	return p.provider().DownTo(ctx, version)
+}
+
+func (p *ReloadingProvider) Up(ctx context.Context) ([]*goose.MigrationResult, error) {
+	return p.provider().Up(ctx)
+}
+
+// UpByOne forwards to the current provider if it is an UpByOner.
+func (p *ReloadingProvider) UpByOne(ctx context.Context) (*goose.MigrationResult, error) {
+	current, ok := p.provider().(UpByOner)
+	if !ok {
+		return nil, errNotImplemented("UpByOne")
+	}
+	return current.UpByOne(ctx)
+}
+
+func (p *ReloadingProvider) UpTo(ctx context.Context, version int64) ([]*goose.MigrationResult, error) {
+	return p.provider().UpTo(ctx, version)
+}
+
+// ApplyVersion forwards to the current provider if it is a VersionApplier.
+func (p *ReloadingProvider) ApplyVersion(ctx context.Context, version int64, direction bool) (*goose.MigrationResult, error) {
+	current, ok := p.provider().(VersionApplier)
+	if !ok {
+		return nil, errNotImplemented("ApplyVersion")
+	}
+	return current.ApplyVersion(ctx, version, direction)
+}
+
+// dirFingerprint summarizes the names, sizes and modification times of the files in dir other than
+// Go sources.
+func dirFingerprint(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for _, entry := range entries {
+		if filepath.Ext(entry.Name()) == ".go" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&sb, "%s %d %d\n", entry.Name(), info.Size(), info.ModTime().UnixNano())
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,139 @@
+package gooseglass_test
+
+import (
+	"bufio"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/typelate/dom/domtest"
+
+	"github.com/crhntr/gooseglass"
+	"github.com/crhntr/gooseglass/internal/fake"
+)
+
+func TestReloadingProvider(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "00001_init.sql"), []byte("-- +goose Up\n"), 0o644))
+
+	var (
+		builds   int
+		buildErr error
+	)
+	build := func() (gooseglass.Provider, error) {
+		if buildErr != nil {
+			return nil, buildErr
+		}
+		builds++
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		provider := new(fake.Provider)
+		var list []*goose.MigrationStatus
+		for i, entry := range entries {
+			list = append(list, &goose.MigrationStatus{
+				State:  goose.StatePending,
+				Source: &goose.Source{Type: goose.TypeSQL, Path: entry.Name(), Version: int64(i + 1)},
+			})
+		}
+		provider.StatusReturns(list, nil)
+		return provider, nil
+	}
+
+	reloading, err := gooseglass.NewReloadingProvider(dir, build)
+	require.NoError(t, err)
+	assert.Equal(t, 1, builds)
+
+	mux := http.NewServeMux()
+	gooseglass.Pages(mux, reloading)
+	rowCount := func() int {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, gooseglass.TemplateRoutePaths{}.Status(), nil))
+		return domtest.ParseResponseDocument(t, rec.Result()).QuerySelectorAll(`#status-table tbody tr`).Length()
+	}
+	assert.Equal(t, 1, rowCount())
+
+	changed, err := reloading.Reload()
+	require.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, 1, builds)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "00002_users.sql"), []byte("-- +goose Up\n"), 0o644))
+	changed, err = reloading.Reload()
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, 2, builds)
+	assert.Equal(t, 2, rowCount())
+
+	buildErr = errors.New("duplicate version 2")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "00002_dupe.sql"), []byte("-- +goose Up\n"), 0o644))
+	_, err = reloading.Reload()
+	assert.ErrorContains(t, err, "duplicate version 2")
+	assert.Equal(t, 2, rowCount(), "keeps the previous provider")
+
+	buildErr = nil
+	changed, err = reloading.Reload()
+	require.NoError(t, err)
+	assert.True(t, changed, "retries after a failed build")
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	res, err := http.Get(srv.URL + "/events")
+	require.NoError(t, err)
+	defer func() { _ = res.Body.Close() }()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "00003_posts.sql"), []byte("-- +goose Up\n"), 0o644))
+	_, err = reloading.Reload()
+	require.NoError(t, err)
+	line, err := bufio.NewReader(res.Body).ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "event: refreshMigrations\n", line)
+}
+
+func TestReloadingProvider_goMigrations(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "00001_init.sql"), []byte("-- +goose Up\n"), 0o644))
+	var builds int
+	build := func() (gooseglass.Provider, error) {
+		// Like goose, fail on Go sources that were not registered when the program started.
+		matches, err := filepath.Glob(filepath.Join(dir, "*.go"))
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) > 0 {
+			return nil, errors.New("go functions must be registered")
+		}
+		builds++
+		return new(fake.Provider), nil
+	}
+	reloading, err := gooseglass.NewReloadingProvider(dir, build)
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	gooseglass.Pages(mux, reloading, gooseglass.WithDevMode(gooseglass.DevMode{Dir: dir, Sequential: true}))
+	req := httptest.NewRequest(http.MethodPost, gooseglass.TemplateRoutePaths{}.NewMigration(), strings.NewReader(url.Values{"name": {"backfill"}, "type": {"go"}}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	res := rec.Result()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	created := domtest.ParseResponseDocument(t, res).QuerySelector(`.new-migration[data-rebuild-required]`)
+	require.NotNil(t, created)
+	assert.Contains(t, created.TextContent(), "00002_backfill.go")
+
+	changed, err := reloading.Reload()
+	require.NoError(t, err)
+	assert.False(t, changed, "Go sources need a rebuild, not a reload")
+	assert.Equal(t, 1, builds)
+
+	assert.Error(t, reloading.Watch(t.Context(), 0))
+}
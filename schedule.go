@@ -0,0 +1,464 @@
+package gooseglass
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pressly/goose/v3"
+)
+
+const (
+	scheduleStatePending   = "pending"
+	scheduleStateRunning   = "running"
+	scheduleStateHeld      = "held"
+	scheduleStateDone      = "done"
+	scheduleStateFailed    = "failed"
+	scheduleStateCancelled = "cancelled"
+)
+
+// Schedule is a run of UpTo queued for a later time. When approvals are configured a due schedule
+// is held for approval like any other run and ApprovalID names the request.
+type Schedule struct {
+	ID         string        `json:"id"`
+	Version    int64         `json:"version"`
+	At         time.Time     `json:"at"`
+	Actor      string        `json:"actor,omitempty"`
+	State      string        `json:"state"`
+	RunID      string        `json:"run_id,omitempty"`
+	ApprovalID string        `json:"approval_id,omitempty"`
+	Entries    []LedgerEntry `json:"entries,omitempty"`
+	Error      string        `json:"error,omitempty"`
+}
+
+func (schedule Schedule) IsPending() bool { return schedule.State == scheduleStatePending }
+
+// Results returns the entries as goose results so they render like an immediate run.
+func (schedule Schedule) Results() []*goose.MigrationResult {
+	list := make([]*goose.MigrationResult, 0, len(schedule.Entries))
+	for _, entry := range schedule.Entries {
+		result := &goose.MigrationResult{
+			Source:    &goose.Source{Type: goose.TypeGo, Path: entry.Path, Version: entry.Version},
+			Duration:  entry.Duration,
+			Direction: entry.Direction,
+		}
+		if strings.HasSuffix(entry.Path, ".sql") {
+			result.Source.Type = goose.TypeSQL
+		}
+		if entry.Error != "" {
+			result.Error = errors.New(entry.Error)
+		}
+		list = append(list, result)
+	}
+	return list
+}
+
+// ScheduleStore persists scheduled runs. Save inserts or replaces the schedule with the same ID.
+type ScheduleStore interface {
+	Save(ctx context.Context, schedule Schedule) error
+	List(ctx context.Context) ([]Schedule, error)
+}
+
+// MemoryScheduleStore keeps schedules until the process exits.
+type MemoryScheduleStore struct {
+	mu        sync.Mutex
+	schedules []Schedule
+}
+
+func NewMemoryScheduleStore() *MemoryScheduleStore { return new(MemoryScheduleStore) }
+
+func (store *MemoryScheduleStore) Save(_ context.Context, schedule Schedule) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.schedules = upsertSchedule(store.schedules, schedule)
+	return nil
+}
+
+func (store *MemoryScheduleStore) List(context.Context) ([]Schedule, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return slices.Clone(store.schedules), nil
+}
+
+// FileScheduleStore keeps schedules in a JSON file, replacing it on every Save.
+type FileScheduleStore struct {
+	mu   sync.Mutex
+	name string
+}
+
+func NewFileScheduleStore(name string) *FileScheduleStore { return &FileScheduleStore{name: name} }
+
+func (store *FileScheduleStore) Save(_ context.Context, schedule Schedule) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	list, err := store.read()
+	if err != nil {
+		return err
+	}
+	buf, err := json.MarshalIndent(upsertSchedule(list, schedule), "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(store.name), filepath.Base(store.name)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(buf); err != nil {
+		return errors.Join(err, tmp.Close(), os.Remove(tmp.Name()))
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Join(err, os.Remove(tmp.Name()))
+	}
+	return os.Rename(tmp.Name(), store.name)
+}
+
+func (store *FileScheduleStore) List(context.Context) ([]Schedule, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.read()
+}
+
+func (store *FileScheduleStore) read() ([]Schedule, error) {
+	buf, err := os.ReadFile(store.name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var list []Schedule
+	return list, json.Unmarshal(buf, &list)
+}
+
+func upsertSchedule(list []Schedule, schedule Schedule) []Schedule {
+	if i := slices.IndexFunc(list, func(s Schedule) bool { return s.ID == schedule.ID }); i >= 0 {
+		list[i] = schedule
+		return list
+	}
+	return append(list, schedule)
+}
+
+// Scheduler executes scheduled runs once they are due. Pass it to Pages with WithScheduler and
+// call Run to start executing.
+type Scheduler struct {
+	store    ScheduleStore
+	interval time.Duration
+
+	mu     sync.Mutex
+	server *server
+}
+
+// NewScheduler checks store for due schedules every interval, every 30 seconds when zero.
+func NewScheduler(store ScheduleStore, interval time.Duration) *Scheduler {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	return &Scheduler{store: store, interval: interval}
+}
+
+// WithScheduler adds the schedules page and lets scheduler execute runs.
+func WithScheduler(scheduler *Scheduler) Option {
+	return func(s *server) {
+		s.scheduler = scheduler
+		scheduler.server = s
+	}
+}
+
+// Run executes due schedules until ctx is cancelled. Schedules left running by a process that
+// stopped mid-run are marked failed first, because what they applied is unknown.
+func (scheduler *Scheduler) Run(ctx context.Context) error {
+	if err := scheduler.failInterrupted(ctx); err != nil {
+		return err
+	}
+	ticker := time.NewTicker(scheduler.interval)
+	defer ticker.Stop()
+	for {
+		if err := scheduler.Tick(ctx, time.Now()); err != nil {
+			slog.ErrorContext(ctx, "failed to run schedules", slog.String("error", err.Error()))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick settles held schedules whose approval expired and executes the pending schedules due at
+// now, oldest first. Each schedule is tried once per Tick.
+func (scheduler *Scheduler) Tick(ctx context.Context, now time.Time) error {
+	if scheduler.server == nil {
+		return errors.New("scheduler is not registered with Pages")
+	}
+	if err := scheduler.settleExpired(ctx, now); err != nil {
+		return err
+	}
+	tried := make(map[string]bool)
+	for {
+		schedule, ok, err := scheduler.claim(ctx, now, tried)
+		if err != nil || !ok {
+			return err
+		}
+		tried[schedule.ID] = true
+		if err := scheduler.execute(ctx, schedule); err != nil {
+			return err
+		}
+	}
+}
+
+// claim marks the oldest due pending schedule not yet tried running, so it can no longer be
+// cancelled while it executes without the lock.
+func (scheduler *Scheduler) claim(ctx context.Context, now time.Time, tried map[string]bool) (Schedule, bool, error) {
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+	list, err := scheduler.store.List(ctx)
+	if err != nil {
+		return Schedule{}, false, err
+	}
+	slices.SortStableFunc(list, func(a, b Schedule) int { return a.At.Compare(b.At) })
+	for _, schedule := range list {
+		if !schedule.IsPending() || schedule.At.After(now) || tried[schedule.ID] {
+			continue
+		}
+		schedule.State = scheduleStateRunning
+		return schedule, true, scheduler.store.Save(ctx, schedule)
+	}
+	return Schedule{}, false, nil
+}
+
+func (scheduler *Scheduler) failInterrupted(ctx context.Context) error {
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+	list, err := scheduler.store.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, schedule := range list {
+		if schedule.State != scheduleStateRunning {
+			continue
+		}
+		schedule.State = scheduleStateFailed
+		schedule.Error = "interrupted before it finished; check the status page for what was applied"
+		if err := scheduler.store.Save(ctx, schedule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// execute runs a claimed schedule. A schedule that finds another run in progress or the maintenance
+// windows closed stays pending and is tried again on the next Tick.
+func (scheduler *Scheduler) execute(ctx context.Context, schedule Schedule) error {
+	result, err := scheduler.server.submit(ctx, Plan{
+		Operation: operationUpTo,
+		Version:   schedule.Version,
+		Actor:     schedule.Actor,
+	})
+	var blocked *windowError
+	if errors.Is(err, errRunInProgress) || errors.As(err, &blocked) {
+		schedule.State = scheduleStatePending
+		return scheduler.store.Save(ctx, schedule)
+	}
+	schedule.State = scheduleStateDone
+	schedule.RunID = result.Run.ID
+	schedule.Entries = result.Run.Entries
+	if result.Approval != nil {
+		schedule.State = scheduleStateHeld
+		schedule.ApprovalID = result.Approval.ID
+	}
+	if err != nil {
+		schedule.State = scheduleStateFailed
+		schedule.Error = err.Error()
+	}
+	return scheduler.store.Save(ctx, schedule)
+}
+
+// settle updates the schedule held for approval once the approval is done. entries are the
+// entries of the approved run, if any.
+func (scheduler *Scheduler) settle(ctx context.Context, approval ApprovalRequest, entries []LedgerEntry) error {
+	if !approval.IsDone() {
+		return nil
+	}
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+	list, err := scheduler.store.List(ctx)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(list, func(schedule Schedule) bool {
+		return schedule.State == scheduleStateHeld && schedule.ApprovalID == approval.ID
+	})
+	if i < 0 {
+		return nil
+	}
+	schedule := list[i]
+	switch approval.State {
+	case approvalStateApproved:
+		schedule.State = scheduleStateDone
+		schedule.RunID, schedule.Entries = approval.RunID, entries
+		if approval.Error != "" {
+			schedule.State, schedule.Error = scheduleStateFailed, approval.Error
+		}
+	case approvalStateRejected:
+		schedule.State = scheduleStateCancelled
+		schedule.Error = fmt.Sprintf("rejected by %s", approval.DecidedBy)
+	default:
+		schedule.State = scheduleStateFailed
+		schedule.Error = fmt.Sprintf("approval expired at %s", approval.ExpiresAt.Format(time.RFC3339))
+	}
+	return scheduler.store.Save(ctx, schedule)
+}
+
+// settleExpired settles the held schedules whose approval expired without a decision.
+func (scheduler *Scheduler) settleExpired(ctx context.Context, now time.Time) error {
+	approvals := scheduler.server.approvals
+	if approvals == nil {
+		return nil
+	}
+	list, err := scheduler.store.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, schedule := range list {
+		if schedule.State != scheduleStateHeld {
+			continue
+		}
+		approval, err := approvals.get(schedule.ApprovalID, now)
+		if err != nil || approval.State != approvalStateExpired {
+			continue
+		}
+		if err := scheduler.settle(ctx, approval, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// settleSchedule settles the schedule held for approval, if any, and logs a failure to save it.
+func (s *server) settleSchedule(ctx context.Context, approval ApprovalRequest, entries []LedgerEntry) {
+	if s.scheduler == nil {
+		return
+	}
+	if err := s.scheduler.settle(ctx, approval, entries); err != nil {
+		slog.ErrorContext(ctx, "failed to update schedule held for approval", slog.String("approval", approval.ID), slog.String("error", err.Error()))
+	}
+}
+
+type scheduleForm struct {
+	Version int64  `name:"version"`
+	At      string `name:"at"`
+}
+
+// scheduleLayout matches the value of a datetime-local input.
+const scheduleLayout = "2006-01-02T15:04"
+
+type schedulesPage struct {
+	Schedules []Schedule
+	Pending   []int64
+}
+
+func (s *server) Schedules(ctx context.Context) (schedulesPage, error) {
+	if s.scheduler == nil {
+		return schedulesPage{}, statusError{code: http.StatusNotFound, err: errors.New("scheduling is not configured")}
+	}
+	list, err := s.scheduler.store.List(ctx)
+	if err != nil {
+		return schedulesPage{}, err
+	}
+	slices.SortStableFunc(list, func(a, b Schedule) int { return b.At.Compare(a.At) })
+	page := schedulesPage{Schedules: list}
+	statuses, err := s.provider.Status(ctx)
+	if err != nil {
+		return schedulesPage{}, err
+	}
+	for _, ms := range statuses {
+		if ms.State == goose.StatePending && ms.Source != nil {
+			page.Pending = append(page.Pending, ms.Source.Version)
+		}
+	}
+	return page, nil
+}
+
+func (s *server) CreateSchedule(ctx context.Context, request *http.Request, form scheduleForm) (schedulesPage, error) {
+	if s.scheduler == nil {
+		return schedulesPage{}, statusError{code: http.StatusNotFound, err: errors.New("scheduling is not configured")}
+	}
+	at, err := time.ParseInLocation(scheduleLayout, form.At, time.UTC)
+	if err != nil {
+		return schedulesPage{}, statusError{code: http.StatusBadRequest, err: fmt.Errorf("invalid time %q, use YYYY-MM-DDTHH:MM in UTC", form.At)}
+	}
+	if !at.After(s.now()) {
+		return schedulesPage{}, statusError{code: http.StatusBadRequest, err: fmt.Errorf("time %s is in the past", form.At)}
+	}
+	if err := s.checkPending(ctx, form.Version); err != nil {
+		return schedulesPage{}, err
+	}
+	if err := s.scheduler.store.Save(ctx, Schedule{
+		ID:      rand.Text(),
+		Version: form.Version,
+		At:      at,
+		Actor:   s.actor(request),
+		State:   scheduleStatePending,
+	}); err != nil {
+		return schedulesPage{}, err
+	}
+	return s.Schedules(ctx)
+}
+
+// checkPending returns a bad request error unless version is a pending migration.
+func (s *server) checkPending(ctx context.Context, version int64) error {
+	if version <= 0 {
+		return statusError{code: http.StatusBadRequest, err: fmt.Errorf("invalid version %d", version)}
+	}
+	statuses, err := s.provider.Status(ctx)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(statuses, func(ms *goose.MigrationStatus) bool {
+		return ms.State == goose.StatePending && ms.Source != nil && ms.Source.Version == version
+	}) {
+		return statusError{code: http.StatusBadRequest, err: fmt.Errorf("version %d is not pending", version)}
+	}
+	return nil
+}
+
+func (s *server) CancelSchedule(ctx context.Context, id string) (schedulesPage, error) {
+	if s.scheduler == nil {
+		return schedulesPage{}, statusError{code: http.StatusNotFound, err: errors.New("scheduling is not configured")}
+	}
+	// Hold the scheduler lock so a schedule can not be cancelled while it starts.
+	s.scheduler.mu.Lock()
+	err := s.cancelSchedule(ctx, id)
+	s.scheduler.mu.Unlock()
+	if err != nil {
+		return schedulesPage{}, err
+	}
+	return s.Schedules(ctx)
+}
+
+func (s *server) cancelSchedule(ctx context.Context, id string) error {
+	list, err := s.scheduler.store.List(ctx)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(list, func(schedule Schedule) bool { return schedule.ID == id })
+	if i < 0 {
+		return statusError{code: http.StatusNotFound, err: fmt.Errorf("schedule %q not found", id)}
+	}
+	if !list[i].IsPending() {
+		return statusError{code: http.StatusConflict, err: fmt.Errorf("schedule %q is %s", id, list[i].State)}
+	}
+	list[i].State = scheduleStateCancelled
+	return s.scheduler.store.Save(ctx, list[i])
+}
@@ -0,0 +1,37 @@
+package gooseglass_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/crhntr/gooseglass"
+)
+
+func TestFileScheduleStore(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "schedules.json")
+	store := gooseglass.NewFileScheduleStore(name)
+
+	list, err := store.List(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	at := time.Date(2026, 1, 2, 2, 0, 0, 0, time.UTC)
+	require.NoError(t, store.Save(t.Context(), gooseglass.Schedule{ID: "a", Version: 3, At: at, State: "pending"}))
+	require.NoError(t, store.Save(t.Context(), gooseglass.Schedule{ID: "b", Version: 4, At: at, State: "pending"}))
+	require.NoError(t, store.Save(t.Context(), gooseglass.Schedule{ID: "a", Version: 3, At: at, State: "done", Entries: []gooseglass.LedgerEntry{
+		{Version: 3, Path: "03_add_index.sql", Direction: "up", Duration: time.Millisecond},
+	}}))
+
+	list, err = gooseglass.NewFileScheduleStore(name).List(t.Context())
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "done", list[0].State)
+	assert.True(t, list[0].At.Equal(at))
+	require.Len(t, list[0].Results(), 1)
+	assert.Equal(t, "03_add_index.sql", list[0].Results()[0].Source.Path)
+	assert.Equal(t, "pending", list[1].State)
+}
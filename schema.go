@@ -0,0 +1,163 @@
+package gooseglass
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SchemaInspector reads the current database schema.
+type SchemaInspector interface {
+	Schema(ctx context.Context) (Schema, error)
+}
+
+type Schema struct {
+	Tables []Table `json:"tables"`
+}
+
+type Table struct {
+	Name        string       `json:"name"`
+	Columns     []Column     `json:"columns"`
+	Indexes     []Index      `json:"indexes,omitempty"`
+	ForeignKeys []ForeignKey `json:"foreign_keys,omitempty"`
+}
+
+type Column struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Nullable bool   `json:"nullable"`
+	Default  string `json:"default,omitempty"`
+}
+
+type Index struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Unique  bool     `json:"unique"`
+}
+
+type ForeignKey struct {
+	Name       string   `json:"name,omitempty"`
+	Columns    []string `json:"columns"`
+	RefTable   string   `json:"ref_table"`
+	RefColumns []string `json:"ref_columns"`
+}
+
+func (c Column) String() string {
+	var sb strings.Builder
+	sb.WriteString(c.Type)
+	if !c.Nullable {
+		sb.WriteString(" NOT NULL")
+	}
+	if c.Default != "" {
+		sb.WriteString(" DEFAULT ")
+		sb.WriteString(c.Default)
+	}
+	return sb.String()
+}
+
+func (i Index) String() string {
+	s := "(" + strings.Join(i.Columns, ", ") + ")"
+	if i.Unique {
+		return "UNIQUE " + s
+	}
+	return s
+}
+
+func (fk ForeignKey) String() string {
+	return "(" + strings.Join(fk.Columns, ", ") + ") REFERENCES " + fk.RefTable + " (" + strings.Join(fk.RefColumns, ", ") + ")"
+}
+
+const (
+	changeAdded   = "added"
+	changeRemoved = "removed"
+	changeChanged = "changed"
+)
+
+type schemaDiff struct {
+	Tables []tableDiff
+}
+
+type tableDiff struct {
+	Name        string
+	Change      string
+	Columns     []itemDiff
+	Indexes     []itemDiff
+	ForeignKeys []itemDiff
+}
+
+type itemDiff struct {
+	Name   string
+	Change string
+	Before string
+	After  string
+}
+
+func diffSchema(before, after Schema) schemaDiff {
+	var diff schemaDiff
+	for _, name := range unionNames(before.Tables, after.Tables, func(t Table) string { return t.Name }) {
+		b, inBefore := findByName(before.Tables, name, func(t Table) string { return t.Name })
+		a, inAfter := findByName(after.Tables, name, func(t Table) string { return t.Name })
+		td := tableDiff{
+			Name:    name,
+			Columns: diffItems(b.Columns, a.Columns, func(c Column) string { return c.Name }),
+			Indexes: diffItems(b.Indexes, a.Indexes, func(i Index) string { return i.Name }),
+			// SQLite foreign keys are unnamed so they are matched by definition.
+			ForeignKeys: diffItems(b.ForeignKeys, a.ForeignKeys, ForeignKey.String),
+		}
+		switch {
+		case !inBefore:
+			td.Change = changeAdded
+		case !inAfter:
+			td.Change = changeRemoved
+		case len(td.Columns) > 0 || len(td.Indexes) > 0 || len(td.ForeignKeys) > 0:
+			td.Change = changeChanged
+		default:
+			continue
+		}
+		diff.Tables = append(diff.Tables, td)
+	}
+	return diff
+}
+
+func diffItems[T fmt.Stringer](before, after []T, name func(T) string) []itemDiff {
+	var list []itemDiff
+	for _, n := range unionNames(before, after, name) {
+		b, inBefore := findByName(before, n, name)
+		a, inAfter := findByName(after, n, name)
+		switch {
+		case !inBefore:
+			list = append(list, itemDiff{Name: n, Change: changeAdded, After: a.String()})
+		case !inAfter:
+			list = append(list, itemDiff{Name: n, Change: changeRemoved, Before: b.String()})
+		case b.String() != a.String():
+			list = append(list, itemDiff{Name: n, Change: changeChanged, Before: b.String(), After: a.String()})
+		}
+	}
+	return list
+}
+
+func unionNames[T any](before, after []T, name func(T) string) []string {
+	var names []string
+	for _, list := range [][]T{before, after} {
+		for _, item := range list {
+			if n := name(item); !slices.Contains(names, n) {
+				names = append(names, n)
+			}
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+func findByName[T any](list []T, n string, name func(T) string) (T, bool) {
+	for _, item := range list {
+		if name(item) == n {
+			return item, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+func (diff schemaDiff) IsEmpty() bool { return len(diff.Tables) == 0 }
@@ -0,0 +1,134 @@
+package gooseglass
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// PostgresInspector reads the schema of a PostgreSQL database from information_schema and the
+// pg_index and pg_constraint catalogs.
+type PostgresInspector struct {
+	db     *sql.DB
+	schema string
+}
+
+// NewPostgresInspector inspects the tables of schema, "public" when empty.
+func NewPostgresInspector(db *sql.DB, schema string) *PostgresInspector {
+	return &PostgresInspector{db: db, schema: cmp.Or(schema, "public")}
+}
+
+func (inspector *PostgresInspector) Schema(ctx context.Context) (Schema, error) {
+	names, err := queryStrings(ctx, inspector.db, `SELECT table_name FROM information_schema.tables WHERE table_schema = $1 AND table_type = 'BASE TABLE' ORDER BY table_name`, inspector.schema)
+	if err != nil {
+		return Schema{}, err
+	}
+	tables := make(map[string]*Table, len(names))
+	schema := Schema{Tables: make([]Table, len(names))}
+	for i, name := range names {
+		schema.Tables[i].Name = name
+		tables[name] = &schema.Tables[i]
+	}
+	if err := inspector.columns(ctx, tables); err != nil {
+		return Schema{}, err
+	}
+	if err := inspector.indexes(ctx, tables); err != nil {
+		return Schema{}, err
+	}
+	if err := inspector.foreignKeys(ctx, tables); err != nil {
+		return Schema{}, err
+	}
+	return schema, nil
+}
+
+func (inspector *PostgresInspector) columns(ctx context.Context, tables map[string]*Table) (err error) {
+	rows, err := inspector.db.QueryContext(ctx, `SELECT table_name, column_name, data_type, is_nullable = 'YES', coalesce(column_default, '')
+		FROM information_schema.columns WHERE table_schema = $1 ORDER BY table_name, ordinal_position`, inspector.schema)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, rows.Close()) }()
+	for rows.Next() {
+		var (
+			table  string
+			column Column
+		)
+		if err := rows.Scan(&table, &column.Name, &column.Type, &column.Nullable, &column.Default); err != nil {
+			return err
+		}
+		if t, ok := tables[table]; ok {
+			t.Columns = append(t.Columns, column)
+		}
+	}
+	return rows.Err()
+}
+
+func (inspector *PostgresInspector) indexes(ctx context.Context, tables map[string]*Table) (err error) {
+	rows, err := inspector.db.QueryContext(ctx, `SELECT t.relname, i.relname, ix.indisunique, a.attname
+		FROM pg_index ix
+		JOIN pg_class t ON t.oid = ix.indrelid
+		JOIN pg_class i ON i.oid = ix.indexrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		JOIN LATERAL unnest(ix.indkey) WITH ORDINALITY AS k(attnum, ord) ON true
+		JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+		WHERE n.nspname = $1
+		ORDER BY t.relname, i.relname, k.ord`, inspector.schema)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, rows.Close()) }()
+	for rows.Next() {
+		var (
+			table, index, column string
+			unique               bool
+		)
+		if err := rows.Scan(&table, &index, &unique, &column); err != nil {
+			return err
+		}
+		t, ok := tables[table]
+		if !ok {
+			continue
+		}
+		if n := len(t.Indexes); n > 0 && t.Indexes[n-1].Name == index {
+			t.Indexes[n-1].Columns = append(t.Indexes[n-1].Columns, column)
+			continue
+		}
+		t.Indexes = append(t.Indexes, Index{Name: index, Unique: unique, Columns: []string{column}})
+	}
+	return rows.Err()
+}
+
+func (inspector *PostgresInspector) foreignKeys(ctx context.Context, tables map[string]*Table) (err error) {
+	rows, err := inspector.db.QueryContext(ctx, `SELECT t.relname, c.conname, rt.relname, a.attname, ra.attname
+		FROM pg_constraint c
+		JOIN pg_class t ON t.oid = c.conrelid
+		JOIN pg_class rt ON rt.oid = c.confrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		JOIN LATERAL unnest(c.conkey, c.confkey) WITH ORDINALITY AS k(attnum, refattnum, ord) ON true
+		JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.attnum
+		JOIN pg_attribute ra ON ra.attrelid = c.confrelid AND ra.attnum = k.refattnum
+		WHERE c.contype = 'f' AND n.nspname = $1
+		ORDER BY t.relname, c.conname, k.ord`, inspector.schema)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, rows.Close()) }()
+	for rows.Next() {
+		var table, name, ref, column, refColumn string
+		if err := rows.Scan(&table, &name, &ref, &column, &refColumn); err != nil {
+			return err
+		}
+		t, ok := tables[table]
+		if !ok {
+			continue
+		}
+		if n := len(t.ForeignKeys); n == 0 || t.ForeignKeys[n-1].Name != name {
+			t.ForeignKeys = append(t.ForeignKeys, ForeignKey{Name: name, RefTable: ref})
+		}
+		fk := &t.ForeignKeys[len(t.ForeignKeys)-1]
+		fk.Columns = append(fk.Columns, column)
+		fk.RefColumns = append(fk.RefColumns, refColumn)
+	}
+	return rows.Err()
+}
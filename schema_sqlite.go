@@ -0,0 +1,132 @@
+package gooseglass
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// SQLiteInspector reads the schema of a SQLite or libSQL database from sqlite_master.
+type SQLiteInspector struct {
+	db *sql.DB
+}
+
+func NewSQLiteInspector(db *sql.DB) *SQLiteInspector { return &SQLiteInspector{db: db} }
+
+func (inspector *SQLiteInspector) Schema(ctx context.Context) (Schema, error) {
+	names, err := queryStrings(ctx, inspector.db, `SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name`)
+	if err != nil {
+		return Schema{}, err
+	}
+	var schema Schema
+	for _, name := range names {
+		table := Table{Name: name}
+		if table.Columns, err = inspector.columns(ctx, name); err != nil {
+			return Schema{}, err
+		}
+		if table.Indexes, err = inspector.indexes(ctx, name); err != nil {
+			return Schema{}, err
+		}
+		if table.ForeignKeys, err = inspector.foreignKeys(ctx, name); err != nil {
+			return Schema{}, err
+		}
+		schema.Tables = append(schema.Tables, table)
+	}
+	return schema, nil
+}
+
+func (inspector *SQLiteInspector) columns(ctx context.Context, table string) (_ []Column, err error) {
+	rows, err := inspector.db.QueryContext(ctx, `SELECT name, type, "notnull", dflt_value, pk FROM pragma_table_info(?) ORDER BY cid`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = errors.Join(err, rows.Close()) }()
+	var list []Column
+	for rows.Next() {
+		var (
+			column  Column
+			notNull bool
+			value   sql.NullString
+			pk      int
+		)
+		if err := rows.Scan(&column.Name, &column.Type, &notNull, &value, &pk); err != nil {
+			return nil, err
+		}
+		column.Nullable = !notNull && pk == 0
+		column.Default = value.String
+		list = append(list, column)
+	}
+	return list, rows.Err()
+}
+
+func (inspector *SQLiteInspector) indexes(ctx context.Context, table string) (_ []Index, err error) {
+	rows, err := inspector.db.QueryContext(ctx, `SELECT name, "unique" FROM pragma_index_list(?) ORDER BY name`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = errors.Join(err, rows.Close()) }()
+	var list []Index
+	for rows.Next() {
+		var index Index
+		if err := rows.Scan(&index.Name, &index.Unique); err != nil {
+			return nil, err
+		}
+		list = append(list, index)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for i := range list {
+		list[i].Columns, err = queryStrings(ctx, inspector.db, `SELECT name FROM pragma_index_info(?) ORDER BY seqno`, list[i].Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+func (inspector *SQLiteInspector) foreignKeys(ctx context.Context, table string) (_ []ForeignKey, err error) {
+	rows, err := inspector.db.QueryContext(ctx, `SELECT id, "table", "from", coalesce("to", '') FROM pragma_foreign_key_list(?) ORDER BY id, seq`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = errors.Join(err, rows.Close()) }()
+	var (
+		list   []ForeignKey
+		lastID = -1
+	)
+	for rows.Next() {
+		var (
+			id            int
+			ref, from, to string
+		)
+		if err := rows.Scan(&id, &ref, &from, &to); err != nil {
+			return nil, err
+		}
+		if id != lastID {
+			list = append(list, ForeignKey{RefTable: ref})
+			lastID = id
+		}
+		fk := &list[len(list)-1]
+		fk.Columns = append(fk.Columns, from)
+		fk.RefColumns = append(fk.RefColumns, to)
+	}
+	return list, rows.Err()
+}
+
+func queryStrings(ctx context.Context, db *sql.DB, query string, args ...any) (_ []string, err error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = errors.Join(err, rows.Close()) }()
+	var list []string
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			return nil, err
+		}
+		list = append(list, s)
+	}
+	return list, rows.Err()
+}
@@ -0,0 +1,470 @@
+package gooseglass
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pressly/goose/v3"
+)
+
+var errRunInProgress = statusError{code: http.StatusConflict, err: errors.New("another run is in progress")}
+
+type server struct {
+	running sync.Mutex
+
+	provider      Provider
+	allowMissing  bool
+	migrations    fs.FS
+	ledger        Ledger
+	inspector     SchemaInspector
+	backups       Backups
+	scheduler     *Scheduler
+	approvals     *Approvals
+	dev           *DevMode
+	environment   *Environment
+	peers         []Peer
+	recentApplied int
+	overrides     []func(template.FuncMap) (*template.Template, error)
+	templateFuncs template.FuncMap
+	actor         func(*http.Request) string
+
+	windows          []MaintenanceWindow
+	emergencyWindows []MaintenanceWindow
+	now              func() time.Time
+
+	hub           hub
+	notifiers     []Notifier
+	notifications notifications
+	beforeMigrate []BeforeMigrateFunc
+	afterMigrate  []AfterMigrateFunc
+}
+
+func (s *server) Status(ctx context.Context, query statusQuery) (statusTable, error) {
+	list, err := s.provider.Status(ctx)
+	if err != nil {
+		// Keep the environment so the banner still shows which database failed.
+		return statusTable{Environment: s.environment}, err
+	}
+	_, applies := s.provider.(VersionApplier)
+	table := newStatusTable(list, query, s.allowMissing && applies, s.recentApplied)
+	table.CanRedo = applies
+	table.HasSchema = s.inspector != nil
+	table.HasBackups = s.backups != nil
+	table.HasSchedules = s.scheduler != nil
+	table.HasApprovals = s.approvals != nil
+	table.DevMode = s.dev != nil
+	table.Environment = s.environment
+	table.HasPeers = len(s.peers) > 0
+	table.Window = newWindowState(s.windows, s.emergencyWindows, s.now())
+	return table, nil
+}
+
+func (s *server) Schema(ctx context.Context) (Schema, error) {
+	if s.inspector == nil {
+		return Schema{}, statusError{code: http.StatusNotFound, err: errors.New("no schema inspector configured")}
+	}
+	return s.inspector.Schema(ctx)
+}
+
+func (s *server) Migration(ctx context.Context, version int64) (migrationDetail, error) {
+	list, err := s.provider.Status(ctx)
+	if err != nil {
+		return migrationDetail{}, err
+	}
+	detail := migrationDetail{Version: version}
+	for _, ms := range list {
+		if ms.Source != nil && ms.Source.Version == version {
+			detail.Status = ms
+			break
+		}
+	}
+	detail.History, err = s.ledger.History(ctx, version)
+	if err != nil {
+		return migrationDetail{}, err
+	}
+	if detail.Status == nil && len(detail.History) == 0 {
+		return migrationDetail{}, statusError{code: http.StatusNotFound, err: fmt.Errorf("migration %d not found", version)}
+	}
+	return detail, nil
+}
+
+func (s *server) Down(ctx context.Context, request *http.Request) (runResult, error) {
+	return s.migrate(ctx, request, Plan{Operation: operationDown, Reason: request.FormValue("override_reason")})
+}
+
+func (s *server) DownTo(ctx context.Context, request *http.Request, version int64) (runResult, error) {
+	return s.migrate(ctx, request, Plan{Operation: operationDownTo, Version: version, Reason: request.FormValue("override_reason")})
+}
+
+func (s *server) Up(ctx context.Context, request *http.Request) (runResult, error) {
+	return s.migrate(ctx, request, Plan{Operation: operationUp})
+}
+
+func (s *server) UpTo(ctx context.Context, request *http.Request, version int64) (runResult, error) {
+	return s.migrate(ctx, request, Plan{Operation: operationUpTo, Version: version})
+}
+
+func (s *server) Redo(ctx context.Context, request *http.Request) (runResult, error) {
+	return s.migrate(ctx, request, Plan{Operation: operationRedo, Reason: request.FormValue("override_reason")})
+}
+
+// redo rolls back the latest migration and applies the same version again. The re-apply is skipped
+// if the roll back fails. UpByOne is not used because with missing migrations it applies the oldest
+// missing one instead.
+func (s *server) redo(ctx context.Context) ([]*goose.MigrationResult, error) {
+	down, err := s.provider.Down(ctx)
+	if err != nil || down == nil || down.Source == nil {
+		return one(down, err)
+	}
+	up, err := s.applyVersion(ctx, down.Source.Version, true)
+	if err == nil {
+		return []*goose.MigrationResult{down, up}, nil
+	}
+	var partial *goose.PartialError
+	if !errors.As(err, &partial) {
+		// Report the re-apply as the failed migration, like goose does for a failed statement.
+		err = &goose.PartialError{Failed: &goose.MigrationResult{Source: down.Source, Direction: "up", Error: err}, Err: err}
+	}
+	return nil, afterRollback([]*goose.MigrationResult{down}, nil, err)
+}
+
+// sequence runs down and then, only if it succeeded, up as one run.
+func sequence(ctx context.Context, down, up func(context.Context) ([]*goose.MigrationResult, error)) ([]*goose.MigrationResult, error) {
+	results, err := down(ctx)
+	if err != nil {
+		return results, err
+	}
+	more, err := up(ctx)
+	if err != nil {
+		return nil, afterRollback(results, more, err)
+	}
+	return append(results, more...), nil
+}
+
+// afterRollback returns a goose.PartialError for a step that failed after rolling back, holding the
+// roll back with the applied results so it is recorded and shown.
+func afterRollback(rolledBack, applied []*goose.MigrationResult, err error) error {
+	var partial *goose.PartialError
+	if errors.As(err, &partial) {
+		return &goose.PartialError{Applied: append(slices.Clone(rolledBack), partial.Applied...), Failed: partial.Failed, Err: partial.Err}
+	}
+	return &goose.PartialError{Applied: append(slices.Clone(rolledBack), applied...), Err: err}
+}
+
+func (s *server) ApplyMissing(ctx context.Context, request *http.Request, version int64) (runResult, error) {
+	if !s.allowMissing {
+		return runResult{}, statusError{code: http.StatusForbidden, err: fmt.Errorf("applying missing migration %d is not allowed", version)}
+	}
+	return s.migrate(ctx, request, Plan{Operation: operationApply, Version: version})
+}
+
+// backupsPage lists the backups with the window state, so a restore outside the emergency windows
+// can give an override reason.
+type backupsPage struct {
+	Backups []Backup
+	Window  *windowState
+}
+
+func (s *server) Backups(ctx context.Context) (backupsPage, error) {
+	if s.backups == nil {
+		return backupsPage{}, statusError{code: http.StatusNotFound, err: errors.New("backups are not configured")}
+	}
+	list, err := s.backups.List(ctx)
+	if err != nil {
+		return backupsPage{}, err
+	}
+	return backupsPage{Backups: list, Window: newWindowState(s.windows, s.emergencyWindows, s.now())}, nil
+}
+
+// RestoreBackup replaces the database with a backup. It counts as a rollback, so it waits for
+// approval and needs an emergency window or an override reason.
+func (s *server) RestoreBackup(ctx context.Context, request *http.Request, name string) (runResult, error) {
+	if s.backups == nil {
+		return runResult{}, statusError{code: http.StatusNotFound, err: errors.New("backups are not configured")}
+	}
+	list, err := s.backups.List(ctx)
+	if err != nil {
+		return runResult{}, err
+	}
+	if !slices.ContainsFunc(list, func(b Backup) bool { return b.Name == name }) {
+		return runResult{}, statusError{code: http.StatusNotFound, err: fmt.Errorf("backup %q not found", name)}
+	}
+	return s.migrate(ctx, request, Plan{Operation: operationRestore, Backup: name, Reason: request.FormValue("override_reason")})
+}
+
+// runResult is rendered by the routes that apply or roll back migrations.
+type runResult struct {
+	Run     Run
+	Results []*goose.MigrationResult
+	Diff    *schemaDiff
+	Backup  *Backup
+
+	// Approval is set instead when the run is waiting for approval.
+	Approval *ApprovalRequest
+}
+
+// Direction returns the results that went "up" or "down".
+func (result runResult) Direction(direction string) []*goose.MigrationResult {
+	var list []*goose.MigrationResult
+	for _, r := range result.Results {
+		if r.Direction == direction {
+			list = append(list, r)
+		}
+	}
+	return list
+}
+
+// migrate executes a run triggered by request.
+func (s *server) migrate(ctx context.Context, request *http.Request, plan Plan) (runResult, error) {
+	plan.Actor = s.actor(request)
+	return s.submit(ctx, plan)
+}
+
+// submit holds the plan for approval when approvals are configured and executes it otherwise.
+func (s *server) submit(ctx context.Context, plan Plan) (runResult, error) {
+	if err := s.supports(plan.Operation); err != nil {
+		return runResult{}, err
+	}
+	if s.approvals != nil {
+		approval := s.approvals.request(ctx, plan, s.now())
+		return runResult{Approval: &approval}, nil
+	}
+	return s.execute(ctx, plan, nil)
+}
+
+// supports returns an error with status 501 if the provider lacks a method the operation needs.
+func (s *server) supports(operation string) error {
+	switch operation {
+	case operationUpByOne:
+		if _, ok := s.provider.(UpByOner); !ok {
+			return errNotImplemented("UpByOne")
+		}
+	case operationApply, operationApplyDown, operationRedo:
+		if _, ok := s.provider.(VersionApplier); !ok {
+			return errNotImplemented("ApplyVersion")
+		}
+	}
+	return nil
+}
+
+func (s *server) upByOne(ctx context.Context) (*goose.MigrationResult, error) {
+	provider, ok := s.provider.(UpByOner)
+	if !ok {
+		return nil, errNotImplemented("UpByOne")
+	}
+	return provider.UpByOne(ctx)
+}
+
+func (s *server) applyVersion(ctx context.Context, version int64, direction bool) (*goose.MigrationResult, error) {
+	provider, ok := s.provider.(VersionApplier)
+	if !ok {
+		return nil, errNotImplemented("ApplyVersion")
+	}
+	return provider.ApplyVersion(ctx, version, direction)
+}
+
+// run calls the provider method for the plan's operation.
+func (s *server) run(ctx context.Context, plan Plan) ([]*goose.MigrationResult, error) {
+	switch plan.Operation {
+	case operationUp:
+		return s.provider.Up(ctx)
+	case operationUpTo:
+		return s.provider.UpTo(ctx, plan.Version)
+	case operationDown:
+		return one(s.provider.Down(ctx))
+	case operationDownTo:
+		return s.provider.DownTo(ctx, plan.Version)
+	case operationUpByOne:
+		return one(s.upByOne(ctx))
+	case operationApply:
+		return one(s.applyVersion(ctx, plan.Version, true))
+	case operationApplyDown:
+		return one(s.applyVersion(ctx, plan.Version, false))
+	case operationRedo:
+		return s.redo(ctx)
+	case operationReset:
+		return s.provider.DownTo(ctx, 0)
+	case operationResetUp:
+		return sequence(ctx, func(ctx context.Context) ([]*goose.MigrationResult, error) {
+			return s.provider.DownTo(ctx, 0)
+		}, s.provider.Up)
+	case operationRestore:
+		return nil, s.backups.Restore(ctx, plan.Backup)
+	default:
+		return nil, fmt.Errorf("unknown operation %q", plan.Operation)
+	}
+}
+
+// execute runs the plan between the before and after hooks and schema snapshots and records the
+// migrations it applied or rolled back in the ledger. Only one run executes at a time. When admit is
+// not nil it is called once the run holds the lock and passed the maintenance windows, and an error
+// from it aborts the run.
+func (s *server) execute(ctx context.Context, plan Plan, admit func() error) (runResult, error) {
+	if !s.running.TryLock() {
+		return runResult{}, errRunInProgress
+	}
+	defer s.running.Unlock()
+	plan.ID = rand.Text()
+	if err := s.checkWindows(plan, s.now()); err != nil {
+		return runResult{}, err
+	}
+	if admit != nil {
+		if err := admit(); err != nil {
+			return runResult{}, err
+		}
+	}
+	for _, hook := range s.beforeMigrate {
+		if err := hook(ctx, plan); err != nil {
+			return runResult{}, hookError{plan: plan, err: err}
+		}
+	}
+	var backup *Backup
+	if s.backups != nil && plan.rollsBack() {
+		b, err := s.backups.Backup(ctx)
+		if err != nil {
+			return runResult{}, fmt.Errorf("backup before %s failed: %w", plan, err)
+		}
+		backup = &b
+	}
+	record := Run{
+		ID:        plan.ID,
+		Operation: plan.String(),
+		Actor:     plan.Actor,
+		Reason:    plan.Reason,
+		Approver:  plan.Approver,
+		Time:      s.now().Round(0),
+	}
+	event := newEvent(plan, record.Time)
+	s.notify(ctx, event)
+	record.SchemaBefore = s.snapshot(ctx)
+	results, err := s.run(ctx, plan)
+	err = s.migrationError(err)
+	if record.SchemaBefore != nil {
+		record.SchemaAfter = s.snapshot(ctx)
+	}
+	record.Entries = ledgerEntries(results, err)
+	// A restore changes the database without applying or rolling back a migration.
+	if len(record.Entries) > 0 || plan.Operation == operationRestore {
+		s.hub.broadcast(eventRefreshMigration)
+		if err := s.ledger.Record(ctx, record); err != nil {
+			slog.ErrorContext(ctx, "failed to record migration run", slog.String("error", err.Error()))
+		}
+	}
+	s.notify(ctx, event.finished(record.Entries, err, s.now()))
+	for _, hook := range s.afterMigrate {
+		hook(ctx, plan, results, err)
+	}
+	result := runResult{Run: record, Results: results, Backup: backup}
+	if record.SchemaBefore != nil && record.SchemaAfter != nil {
+		diff := diffSchema(*record.SchemaBefore, *record.SchemaAfter)
+		result.Diff = &diff
+	}
+	return result, err
+}
+
+func (s *server) snapshot(ctx context.Context) *Schema {
+	if s.inspector == nil {
+		return nil
+	}
+	schema, err := s.inspector.Schema(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to inspect database schema", slog.String("error", err.Error()))
+		return nil
+	}
+	return &schema
+}
+
+func ledgerEntries(results []*goose.MigrationResult, err error) []LedgerEntry {
+	var partial *goose.PartialError
+	if errors.As(err, &partial) {
+		results = append(slices.Clone(partial.Applied), partial.Failed)
+	}
+	entries := make([]LedgerEntry, 0, len(results))
+	for _, result := range results {
+		if result == nil || result.Source == nil {
+			continue
+		}
+		entry := LedgerEntry{
+			Version:   result.Source.Version,
+			Path:      result.Source.Path,
+			Direction: result.Direction,
+			Duration:  result.Duration,
+		}
+		if result.Error != nil {
+			entry.Error = result.Error.Error()
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func defaultActor(request *http.Request) string {
+	if user, _, ok := request.BasicAuth(); ok {
+		return user
+	}
+	return request.RemoteAddr
+}
+
+func one(result *goose.MigrationResult, err error) ([]*goose.MigrationResult, error) {
+	if result == nil {
+		return nil, err
+	}
+	return []*goose.MigrationResult{result}, err
+}
+
+// migrationFailure wraps a goose.PartialError with the source of the failed migration and the
+// details of the driver error.
+type migrationFailure struct {
+	*goose.PartialError
+	Source sqlSection
+	Driver *driverError
+}
+
+func (f *migrationFailure) Unwrap() error { return f.PartialError }
+
+// Error does not assume the failed migration is set, unlike goose.PartialError, because a custom
+// Provider may leave it nil.
+func (f *migrationFailure) Error() string {
+	if f.Failed == nil || f.Failed.Source == nil {
+		return fmt.Sprintf("partial migration error: %v", f.Err)
+	}
+	return f.PartialError.Error()
+}
+
+func (s *server) migrationError(err error) error {
+	var partial *goose.PartialError
+	if !errors.As(err, &partial) {
+		return err
+	}
+	failure := &migrationFailure{PartialError: partial, Driver: newDriverError(partial.Err)}
+	if partial.Failed == nil {
+		return failure
+	}
+	if src := partial.Failed.Source; s.migrations != nil && src != nil && src.Type == goose.TypeSQL {
+		// The source is shown on a best effort basis; the run error is what matters.
+		failure.Source, _ = readSQLSection(s.migrations, src.Path, partial.Failed.Direction)
+		if failure.Driver != nil {
+			failure.Driver.Line, _ = failure.Source.lineAt(failure.Driver.Position)
+		}
+	}
+	return failure
+}
+
+// statusError sets the response status code for the wrapped error.
+type statusError struct {
+	code int
+	err  error
+}
+
+func (e statusError) Error() string   { return e.err.Error() }
+func (e statusError) Unwrap() error   { return e.err }
+func (e statusError) StatusCode() int { return e.code }
@@ -0,0 +1,109 @@
+package gooseglass
+
+import (
+	"bufio"
+	"bytes"
+	"io/fs"
+	"strings"
+)
+
+// sqlSection is the "-- +goose Up" or "-- +goose Down" part of a SQL migration.
+type sqlSection struct {
+	Lines      []sourceLine
+	statements []sqlStatement
+}
+
+type sourceLine struct {
+	Number int
+	Text   string
+}
+
+type sqlStatement struct {
+	line int
+	text string
+}
+
+// readSQLSection reads the section of the SQL migration at name for direction ("up" or "down")
+// without the goose annotations. Statements are split the way goose splits them: at a line ending
+// in a semicolon unless inside a StatementBegin/StatementEnd block.
+func readSQLSection(fsys fs.FS, name, direction string) (sqlSection, error) {
+	buf, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return sqlSection{}, err
+	}
+	var (
+		section   sqlSection
+		current   string
+		block     bool
+		statement strings.Builder
+		start     int
+	)
+	scanner := bufio.NewScanner(bytes.NewReader(buf))
+	for number := 1; scanner.Scan(); number++ {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if annotation, ok := strings.CutPrefix(trimmed, "-- +goose "); ok {
+			switch strings.TrimSpace(annotation) {
+			case "Up":
+				current = "up"
+			case "Down":
+				current = "down"
+			case "StatementBegin":
+				block = true
+			case "StatementEnd":
+				block = false
+				if current == direction && statement.Len() > 0 {
+					section.statements = append(section.statements, sqlStatement{line: start, text: strings.TrimSpace(statement.String())})
+					statement.Reset()
+				}
+			}
+			continue
+		}
+		if current != direction {
+			continue
+		}
+		section.Lines = append(section.Lines, sourceLine{Number: number, Text: line})
+		if statement.Len() == 0 && (trimmed == "" || strings.HasPrefix(trimmed, "--")) {
+			continue
+		}
+		if statement.Len() == 0 {
+			start = number
+		}
+		statement.WriteString(line)
+		statement.WriteByte('\n')
+		if !block && strings.HasSuffix(trimmed, ";") {
+			section.statements = append(section.statements, sqlStatement{line: start, text: strings.TrimSpace(statement.String())})
+			statement.Reset()
+		}
+	}
+	for len(section.Lines) > 0 && strings.TrimSpace(section.Lines[0].Text) == "" {
+		section.Lines = section.Lines[1:]
+	}
+	for len(section.Lines) > 0 && strings.TrimSpace(section.Lines[len(section.Lines)-1].Text) == "" {
+		section.Lines = section.Lines[:len(section.Lines)-1]
+	}
+	return section, scanner.Err()
+}
+
+// String returns the section body.
+func (section sqlSection) String() string {
+	lines := make([]string, 0, len(section.Lines))
+	for _, line := range section.Lines {
+		lines = append(lines, line.Text)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// lineAt returns the file line of a 1-based character position in the failed statement. goose
+// does not report which statement failed, so the line is only known for single statement sections.
+func (section sqlSection) lineAt(position int) (int, bool) {
+	if len(section.statements) != 1 || position < 1 {
+		return 0, false
+	}
+	stmt := section.statements[0]
+	runes := []rune(stmt.text)
+	if position > len(runes) {
+		return 0, false
+	}
+	return stmt.line + strings.Count(string(runes[:position-1]), "\n"), true
+}
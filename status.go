@@ -0,0 +1,199 @@
+package gooseglass
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pressly/goose/v3"
+)
+
+// stateUntracked is the state goose reserves for migrations recorded in the database without a
+// matching source. goose v3.26 does not report it yet, so it is matched by value.
+const stateUntracked goose.State = "untracked"
+
+const (
+	kindApplied   = "applied"
+	kindPending   = "pending"
+	kindMissing   = "missing"
+	kindUntracked = "untracked"
+)
+
+// Sort orders for the status table. The default is oldest version first.
+const (
+	sortVersionDesc = "version-desc"
+	sortAppliedDesc = "applied-desc"
+)
+
+// statusQuery narrows the status table. From and To are an inclusive version range and are
+// ignored when they are not numbers, so a half typed filter does not fail the page.
+type statusQuery struct {
+	State string `name:"state"`
+	From  string `name:"from"`
+	To    string `name:"to"`
+	Path  string `name:"path"`
+	Sort  string `name:"sort"`
+	// Before loads the page of applied migrations older than this version.
+	Before string `name:"before"`
+}
+
+func (query statusQuery) matches(row migrationRow) bool {
+	if query.State != "" && query.State != row.Kind {
+		return false
+	}
+	if query.From == "" && query.To == "" && query.Path == "" {
+		return true
+	}
+	if row.Source == nil {
+		return false
+	}
+	if from, err := strconv.ParseInt(query.From, 10, 64); err == nil && row.Source.Version < from {
+		return false
+	}
+	if to, err := strconv.ParseInt(query.To, 10, 64); err == nil && row.Source.Version > to {
+		return false
+	}
+	return strings.Contains(strings.ToLower(row.Source.Path), strings.ToLower(query.Path))
+}
+
+func (query statusQuery) sort(rows []migrationRow) {
+	switch query.Sort {
+	case sortVersionDesc:
+		slices.Reverse(rows)
+	case sortAppliedDesc:
+		// Most recently applied first, then pending in version order.
+		slices.SortStableFunc(rows, func(a, b migrationRow) int {
+			if a.AppliedAt.IsZero() != b.AppliedAt.IsZero() {
+				if a.AppliedAt.IsZero() {
+					return 1
+				}
+				return -1
+			}
+			return b.AppliedAt.Compare(a.AppliedAt)
+		})
+	}
+}
+
+// collapses reports whether older applied migrations are hidden behind a loader row. Searches
+// show every match and the recently applied order has no single older end.
+func (query statusQuery) collapses() bool {
+	return query.From == "" && query.To == "" && query.Path == "" && query.Sort != sortAppliedDesc
+}
+
+// Filtered reports whether the query hides any migrations.
+func (query statusQuery) Filtered() bool {
+	return cmp.Or(query.State, query.From, query.To, query.Path) != ""
+}
+
+type statusTable struct {
+	Migrations   []migrationRow
+	DBVersion    int64
+	AllowMissing bool
+	CanRedo      bool
+	HasSchema    bool
+	HasBackups   bool
+	HasSchedules bool
+	HasApprovals bool
+	HasPeers     bool
+	DevMode      bool
+	Environment  *Environment
+	Window       *windowState
+	Query        statusQuery
+	// Older is how many applied migrations before OlderBefore are not shown yet.
+	Older       int
+	OlderBefore int64
+
+	counts map[string]int
+}
+
+type migrationRow struct {
+	*goose.MigrationStatus
+	Kind string
+}
+
+// newStatusTable builds the table for the query. When recent is positive, only that many of the
+// latest applied migrations are shown with everything not yet applied.
+func newStatusTable(list []*goose.MigrationStatus, query statusQuery, allowMissing bool, recent int) statusTable {
+	table := statusTable{
+		AllowMissing: allowMissing,
+		Query:        query,
+		counts:       make(map[string]int),
+	}
+	for _, ms := range list {
+		if ms.State != goose.StatePending && ms.Source != nil {
+			table.DBVersion = max(table.DBVersion, ms.Source.Version)
+		}
+	}
+	for _, ms := range list {
+		row := migrationRow{MigrationStatus: ms, Kind: migrationKind(ms, table.DBVersion)}
+		table.counts[row.Kind]++
+		if !query.matches(row) {
+			continue
+		}
+		table.Migrations = append(table.Migrations, row)
+	}
+	if recent > 0 && query.collapses() {
+		table.collapse(recent)
+	}
+	query.sort(table.Migrations)
+	return table
+}
+
+func migrationKind(ms *goose.MigrationStatus, dbVersion int64) string {
+	switch {
+	case ms.State == stateUntracked:
+		return kindUntracked
+	case !ms.AppliedAt.IsZero():
+		return kindApplied
+	case ms.Source != nil && ms.Source.Version < dbVersion:
+		return kindMissing
+	default:
+		return kindPending
+	}
+}
+
+// collapse hides all but the latest recent applied migrations. For a Before page it keeps only
+// applied migrations older than Before.
+func (table *statusTable) collapse(recent int) {
+	before, paging := int64(0), table.Query.Before != ""
+	if paging {
+		var err error
+		if before, err = strconv.ParseInt(table.Query.Before, 10, 64); err != nil {
+			paging = false
+		}
+	}
+	applied := 0
+	for _, row := range table.Migrations {
+		if row.IsApplied() && (!paging || row.Source.Version < before) {
+			applied++
+		}
+	}
+	skip := max(applied-recent, 0)
+	kept := table.Migrations[:0]
+	for _, row := range table.Migrations {
+		switch {
+		case !row.IsApplied():
+			if paging {
+				continue
+			}
+		case row.Source == nil || paging && row.Source.Version >= before:
+			continue
+		case skip > 0:
+			skip--
+			table.Older++
+			continue
+		case table.OlderBefore == 0:
+			table.OlderBefore = row.Source.Version
+		}
+		kept = append(kept, row)
+	}
+	table.Migrations = kept
+}
+
+// Count returns the number of migrations of the kind before filtering.
+func (table statusTable) Count(kind string) int { return table.counts[kind] }
+
+func (row migrationRow) IsApplied() bool   { return row.Kind == kindApplied }
+func (row migrationRow) IsMissing() bool   { return row.Kind == kindMissing }
+func (row migrationRow) IsUntracked() bool { return row.Kind == kindUntracked }
@@ -1,19 +1,148 @@
 package gooseglass
 
 import (
+	"context"
 	"embed"
+	"errors"
+	"fmt"
 	"html/template"
+	"io/fs"
 	"net/http"
+	"time"
+
+	"github.com/pressly/goose/v3"
 )
 
 //go:embed *.gohtml
 var templateFiles embed.FS
 
-//go:generate go run github.com/typelate/muxt generate --use-receiver-type=Provider --use-receiver-type-package=github.com/pressly/goose/v3 --output-receiver-interface=Provider --output-routes-func routes --output-template-data-type templateData
+//go:generate go run github.com/typelate/muxt generate --use-receiver-type=server --output-receiver-interface=routesReceiver --output-routes-func routes --output-template-data-type templateData
+//go:generate go run ./internal/routestemplates
 var templates = template.Must(template.ParseFS(templateFiles, "*"))
 
-func Pages(mux *http.ServeMux, provider Provider) { routes(mux, provider) }
+// Provider is the subset of *goose.Provider the pages use.
+type Provider interface {
+	Status(ctx context.Context) ([]*goose.MigrationStatus, error)
+	Down(ctx context.Context) (*goose.MigrationResult, error)
+	DownTo(ctx context.Context, version int64) ([]*goose.MigrationResult, error)
+	Up(ctx context.Context) ([]*goose.MigrationResult, error)
+	UpTo(ctx context.Context, version int64) ([]*goose.MigrationResult, error)
+}
+
+// UpByOner is implemented by providers, such as *goose.Provider, that can apply just the next
+// pending migration. The JSON API up-by-one endpoint needs it.
+type UpByOner interface {
+	UpByOne(ctx context.Context) (*goose.MigrationResult, error)
+}
+
+// VersionApplier is implemented by providers, such as *goose.Provider, that can apply or roll back
+// a single version. Redo and applying missing migrations need it; their buttons are hidden without
+// it.
+type VersionApplier interface {
+	ApplyVersion(ctx context.Context, version int64, direction bool) (*goose.MigrationResult, error)
+}
+
+// errNotImplemented reports a run that needs a method the provider does not have.
+func errNotImplemented(method string) error {
+	return statusError{code: http.StatusNotImplemented, err: fmt.Errorf("the provider does not implement %s", method)}
+}
+
+// Option configures the pages registered by Pages.
+type Option func(*server)
+
+// WithAllowMissing enables applying missing (out-of-order) migrations one at a time from the status
+// table using Provider.ApplyVersion. Pair it with goose.WithAllowOutofOrder if Up should apply them
+// too.
+func WithAllowMissing(allow bool) Option {
+	return func(s *server) { s.allowMissing = allow }
+}
+
+// WithMigrationsFS gives the pages read access to the migration sources. Pass the same fs.FS given
+// to goose.NewProvider.
+func WithMigrationsFS(fsys fs.FS) Option {
+	return func(s *server) { s.migrations = fsys }
+}
+
+// WithLedger sets where the history shown on the migration pages is kept. The default is a
+// MemoryLedger of the last 100 runs.
+func WithLedger(ledger Ledger) Option {
+	return func(s *server) { s.ledger = ledger }
+}
+
+// WithActor sets how the person triggering a run is named in the ledger. The default is the basic
+// auth user name or else the remote address, neither of which is verified, so WithApprovals
+// requires an actor from an identity your authentication checked.
+func WithActor(actor func(*http.Request) string) Option {
+	return func(s *server) { s.actor = actor }
+}
+
+// WithSchemaInspector snapshots the schema before and after every run so the result shows what
+// changed.
+func WithSchemaInspector(inspector SchemaInspector) Option {
+	return func(s *server) { s.inspector = inspector }
+}
+
+// defaultRecentApplied is how many applied migrations the status table shows before loading more.
+const defaultRecentApplied = 20
+
+// WithRecentApplied sets how many of the latest applied migrations the status table shows with
+// the pending ones. Older applied migrations load on request. Zero or less shows every migration.
+func WithRecentApplied(n int) Option {
+	return func(s *server) { s.recentApplied = n }
+}
+
+func Pages(mux *http.ServeMux, provider Provider, options ...Option) {
+	s := &server{provider: provider, ledger: NewMemoryLedger(0), now: time.Now, recentApplied: defaultRecentApplied}
+	for _, o := range options {
+		o(s)
+	}
+	if s.actor == nil {
+		if s.approvals != nil {
+			panic(errApprovalsWithoutActor)
+		}
+		s.actor = defaultActor
+	}
+	set, err := s.templateSet()
+	if err != nil {
+		panic(err)
+	}
+	if reloading, ok := provider.(*ReloadingProvider); ok {
+		reloading.onReload(func() { s.hub.broadcast(eventRefreshMigration) })
+	}
+	routes(mux, s, set)
+	mux.HandleFunc("GET "+eventsPath, s.events)
+	s.apiRoutes(mux)
+	mux.HandleFunc("GET "+exportStatusPath, s.exportStatus)
+	mux.HandleFunc("GET "+exportRunPath, s.exportRun)
+}
 
 func (td *templateData[R, T]) TriggerRefreshMigrations() *templateData[R, T] {
 	return td.Header("HX-Trigger", `{"refreshMigrations":{"target":"#status-table"}}`)
 }
+
+// StatusCodeFromError sets the response status code from an error with a StatusCode method.
+func (td *templateData[R, T]) StatusCodeFromError() *templateData[R, T] {
+	var sc interface{ StatusCode() int }
+	if errors.As(td.Err(), &sc) {
+		return td.StatusCode(sc.StatusCode())
+	}
+	return td
+}
+
+// WindowBlocked returns why a maintenance window policy blocked the run.
+func (td *templateData[R, T]) WindowBlocked() *windowError {
+	var blocked *windowError
+	if errors.As(td.Err(), &blocked) {
+		return blocked
+	}
+	return nil
+}
+
+// MigrationFailure returns the details of a run that failed after it started applying migrations.
+func (td *templateData[R, T]) MigrationFailure() *migrationFailure {
+	var failure *migrationFailure
+	if errors.As(td.Err(), &failure) {
+		return failure
+	}
+	return nil
+}
@@ -1,4 +1,4 @@
-// Code generated by muxt generate --use-receiver-type=Provider --use-receiver-type-package=github.com/pressly/goose/v3 --output-receiver-interface=Provider --output-routes-func=routes --output-template-data-type=templateData. DO NOT EDIT.
+// Code generated by muxt generate --use-receiver-type=server --output-receiver-interface=routesReceiver --output-routes-func=routes --output-template-data-type=templateData. DO NOT EDIT.
 // muxt version: v0.19.1
 
 package gooseglass
@@ -9,30 +9,118 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"html/template"
 	"log/slog"
 	"net/http"
 	"path"
 	"strconv"
-
-	goose "github.com/pressly/goose/v3"
 )
 
-type Provider interface {
-	Status(ctx context.Context) ([]*goose.MigrationStatus, error)
-	Down(ctx context.Context) (*goose.MigrationResult, error)
-	DownTo(ctx context.Context, version int64) ([]*goose.MigrationResult, error)
-	Up(ctx context.Context) ([]*goose.MigrationResult, error)
-	UpTo(ctx context.Context, version int64) ([]*goose.MigrationResult, error)
+type routesReceiver interface {
+	Status(ctx context.Context, query statusQuery) (statusTable, error)
+	ApplyMissing(ctx context.Context, request *http.Request, version int64) (runResult, error)
+	Approvals(_ context.Context, request *http.Request) (approvalsPage, error)
+	Approve(ctx context.Context, request *http.Request, id string) (runResult, error)
+	Reject(ctx context.Context, request *http.Request, id string) (approvalsPage, error)
+	Backups(ctx context.Context) (backupsPage, error)
+	RestoreBackup(ctx context.Context, request *http.Request, name string) (runResult, error)
+	Compare(ctx context.Context) (comparison, error)
+	Down(ctx context.Context, request *http.Request) (runResult, error)
+	DownTo(ctx context.Context, request *http.Request, version int64) (runResult, error)
+	NewMigration(_ context.Context, form newMigrationForm) (newMigration, error)
+	Migration(ctx context.Context, version int64) (migrationDetail, error)
+	Redo(ctx context.Context, request *http.Request) (runResult, error)
+	Reset(ctx context.Context, request *http.Request) (runResult, error)
+	Schedules(ctx context.Context) (schedulesPage, error)
+	CreateSchedule(ctx context.Context, request *http.Request, form scheduleForm) (schedulesPage, error)
+	CancelSchedule(ctx context.Context, id string) (schedulesPage, error)
+	Schema(ctx context.Context) (Schema, error)
+	Up(ctx context.Context, request *http.Request) (runResult, error)
+	UpTo(ctx context.Context, request *http.Request, version int64) (runResult, error)
 }
 
-func routes(mux *http.ServeMux, receiver Provider) TemplateRoutePaths {
+func routes(mux *http.ServeMux, receiver routesReceiver, templates *template.Template) TemplateRoutePaths {
 	pathsPrefix := ""
 	mux.HandleFunc("GET /", func(response http.ResponseWriter, request *http.Request) {
-		var td = templateData[Provider, []*goose.MigrationStatus]{receiver: receiver, response: response, request: request, pathsPrefix: pathsPrefix}
+		var td = templateData[routesReceiver, statusTable]{receiver: receiver, response: response, request: request, pathsPrefix: pathsPrefix}
+		ctx := request.Context()
+		request.ParseForm()
+		var form statusQuery
+		form.State = request.FormValue("state")
+		form.From = request.FormValue("from")
+		form.To = request.FormValue("to")
+		form.Path = request.FormValue("path")
+		form.Sort = request.FormValue("sort")
+		form.Before = request.FormValue("before")
+		if len(td.errList) == 0 {
+			var err error
+			td.result, err = receiver.Status(ctx, form)
+			if err != nil {
+				td.errList = append(td.errList, err)
+				td.errStatusCode = http.StatusInternalServerError
+			}
+			td.result = td.result
+		}
+		buf := bytes.NewBuffer(nil)
+		if err := templates.ExecuteTemplate(buf, "GET / Status(ctx, form)", &td); err != nil {
+			slog.ErrorContext(request.Context(), "failed to render page", slog.String("path", request.URL.Path), slog.String("pattern", request.Pattern), slog.String("error", err.Error()))
+			http.Error(response, "failed to render page", http.StatusInternalServerError)
+			return
+		}
+		statusCode := cmp.Or(td.statusCode, td.errStatusCode, http.StatusOK)
+		if td.redirectURL != "" {
+			http.Redirect(response, request, td.redirectURL, statusCode)
+			return
+		}
+		if contentType := response.Header().Get("content-type"); contentType == "" {
+			response.Header().Set("content-type", "text/html; charset=utf-8")
+		}
+		response.Header().Set("content-length", strconv.Itoa(buf.Len()))
+		response.WriteHeader(statusCode)
+		_, _ = buf.WriteTo(response)
+	})
+	mux.HandleFunc("POST /apply/{version}", func(response http.ResponseWriter, request *http.Request) {
+		var td = templateData[routesReceiver, runResult]{receiver: receiver, response: response, request: request, pathsPrefix: pathsPrefix}
+		ctx := request.Context()
+		versionParsed, err := strconv.ParseInt(request.PathValue("version"), 10, 64)
+		if err != nil {
+			td.errList = append(td.errList, err)
+			td.errStatusCode = http.StatusBadRequest
+		}
+		version := versionParsed
+		if len(td.errList) == 0 {
+			var err error
+			td.result, err = receiver.ApplyMissing(ctx, request, version)
+			if err != nil {
+				td.errList = append(td.errList, err)
+				td.errStatusCode = http.StatusInternalServerError
+			}
+			td.result = td.result
+		}
+		buf := bytes.NewBuffer(nil)
+		if err := templates.ExecuteTemplate(buf, "POST /apply/{version} ApplyMissing(ctx, request, version)", &td); err != nil {
+			slog.ErrorContext(request.Context(), "failed to render page", slog.String("path", request.URL.Path), slog.String("pattern", request.Pattern), slog.String("error", err.Error()))
+			http.Error(response, "failed to render page", http.StatusInternalServerError)
+			return
+		}
+		statusCode := cmp.Or(td.statusCode, td.errStatusCode, http.StatusOK)
+		if td.redirectURL != "" {
+			http.Redirect(response, request, td.redirectURL, statusCode)
+			return
+		}
+		if contentType := response.Header().Get("content-type"); contentType == "" {
+			response.Header().Set("content-type", "text/html; charset=utf-8")
+		}
+		response.Header().Set("content-length", strconv.Itoa(buf.Len()))
+		response.WriteHeader(statusCode)
+		_, _ = buf.WriteTo(response)
+	})
+	mux.HandleFunc("GET /approvals", func(response http.ResponseWriter, request *http.Request) {
+		var td = templateData[routesReceiver, approvalsPage]{receiver: receiver, response: response, request: request, pathsPrefix: pathsPrefix}
 		ctx := request.Context()
 		if len(td.errList) == 0 {
 			var err error
-			td.result, err = receiver.Status(ctx)
+			td.result, err = receiver.Approvals(ctx, request)
 			if err != nil {
 				td.errList = append(td.errList, err)
 				td.errStatusCode = http.StatusInternalServerError
@@ -40,7 +128,160 @@ func routes(mux *http.ServeMux, receiver Provider) TemplateRoutePaths {
 			td.result = td.result
 		}
 		buf := bytes.NewBuffer(nil)
-		if err := templates.ExecuteTemplate(buf, "GET / Status(ctx)", &td); err != nil {
+		if err := templates.ExecuteTemplate(buf, "GET /approvals Approvals(ctx, request)", &td); err != nil {
+			slog.ErrorContext(request.Context(), "failed to render page", slog.String("path", request.URL.Path), slog.String("pattern", request.Pattern), slog.String("error", err.Error()))
+			http.Error(response, "failed to render page", http.StatusInternalServerError)
+			return
+		}
+		statusCode := cmp.Or(td.statusCode, td.errStatusCode, http.StatusOK)
+		if td.redirectURL != "" {
+			http.Redirect(response, request, td.redirectURL, statusCode)
+			return
+		}
+		if contentType := response.Header().Get("content-type"); contentType == "" {
+			response.Header().Set("content-type", "text/html; charset=utf-8")
+		}
+		response.Header().Set("content-length", strconv.Itoa(buf.Len()))
+		response.WriteHeader(statusCode)
+		_, _ = buf.WriteTo(response)
+	})
+	mux.HandleFunc("POST /approvals/{id}/approve", func(response http.ResponseWriter, request *http.Request) {
+		var td = templateData[routesReceiver, runResult]{receiver: receiver, response: response, request: request, pathsPrefix: pathsPrefix}
+		ctx := request.Context()
+		id := request.PathValue("id")
+		if len(td.errList) == 0 {
+			var err error
+			td.result, err = receiver.Approve(ctx, request, id)
+			if err != nil {
+				td.errList = append(td.errList, err)
+				td.errStatusCode = http.StatusInternalServerError
+			}
+			td.result = td.result
+		}
+		buf := bytes.NewBuffer(nil)
+		if err := templates.ExecuteTemplate(buf, "POST /approvals/{id}/approve Approve(ctx, request, id)", &td); err != nil {
+			slog.ErrorContext(request.Context(), "failed to render page", slog.String("path", request.URL.Path), slog.String("pattern", request.Pattern), slog.String("error", err.Error()))
+			http.Error(response, "failed to render page", http.StatusInternalServerError)
+			return
+		}
+		statusCode := cmp.Or(td.statusCode, td.errStatusCode, http.StatusOK)
+		if td.redirectURL != "" {
+			http.Redirect(response, request, td.redirectURL, statusCode)
+			return
+		}
+		if contentType := response.Header().Get("content-type"); contentType == "" {
+			response.Header().Set("content-type", "text/html; charset=utf-8")
+		}
+		response.Header().Set("content-length", strconv.Itoa(buf.Len()))
+		response.WriteHeader(statusCode)
+		_, _ = buf.WriteTo(response)
+	})
+	mux.HandleFunc("POST /approvals/{id}/reject", func(response http.ResponseWriter, request *http.Request) {
+		var td = templateData[routesReceiver, approvalsPage]{receiver: receiver, response: response, request: request, pathsPrefix: pathsPrefix}
+		ctx := request.Context()
+		id := request.PathValue("id")
+		if len(td.errList) == 0 {
+			var err error
+			td.result, err = receiver.Reject(ctx, request, id)
+			if err != nil {
+				td.errList = append(td.errList, err)
+				td.errStatusCode = http.StatusInternalServerError
+			}
+			td.result = td.result
+		}
+		buf := bytes.NewBuffer(nil)
+		if err := templates.ExecuteTemplate(buf, "POST /approvals/{id}/reject Reject(ctx, request, id)", &td); err != nil {
+			slog.ErrorContext(request.Context(), "failed to render page", slog.String("path", request.URL.Path), slog.String("pattern", request.Pattern), slog.String("error", err.Error()))
+			http.Error(response, "failed to render page", http.StatusInternalServerError)
+			return
+		}
+		statusCode := cmp.Or(td.statusCode, td.errStatusCode, http.StatusOK)
+		if td.redirectURL != "" {
+			http.Redirect(response, request, td.redirectURL, statusCode)
+			return
+		}
+		if contentType := response.Header().Get("content-type"); contentType == "" {
+			response.Header().Set("content-type", "text/html; charset=utf-8")
+		}
+		response.Header().Set("content-length", strconv.Itoa(buf.Len()))
+		response.WriteHeader(statusCode)
+		_, _ = buf.WriteTo(response)
+	})
+	mux.HandleFunc("GET /backups", func(response http.ResponseWriter, request *http.Request) {
+		var td = templateData[routesReceiver, backupsPage]{receiver: receiver, response: response, request: request, pathsPrefix: pathsPrefix}
+		ctx := request.Context()
+		if len(td.errList) == 0 {
+			var err error
+			td.result, err = receiver.Backups(ctx)
+			if err != nil {
+				td.errList = append(td.errList, err)
+				td.errStatusCode = http.StatusInternalServerError
+			}
+			td.result = td.result
+		}
+		buf := bytes.NewBuffer(nil)
+		if err := templates.ExecuteTemplate(buf, "GET /backups Backups(ctx)", &td); err != nil {
+			slog.ErrorContext(request.Context(), "failed to render page", slog.String("path", request.URL.Path), slog.String("pattern", request.Pattern), slog.String("error", err.Error()))
+			http.Error(response, "failed to render page", http.StatusInternalServerError)
+			return
+		}
+		statusCode := cmp.Or(td.statusCode, td.errStatusCode, http.StatusOK)
+		if td.redirectURL != "" {
+			http.Redirect(response, request, td.redirectURL, statusCode)
+			return
+		}
+		if contentType := response.Header().Get("content-type"); contentType == "" {
+			response.Header().Set("content-type", "text/html; charset=utf-8")
+		}
+		response.Header().Set("content-length", strconv.Itoa(buf.Len()))
+		response.WriteHeader(statusCode)
+		_, _ = buf.WriteTo(response)
+	})
+	mux.HandleFunc("POST /backups/{name}/restore", func(response http.ResponseWriter, request *http.Request) {
+		var td = templateData[routesReceiver, runResult]{receiver: receiver, response: response, request: request, pathsPrefix: pathsPrefix}
+		ctx := request.Context()
+		name := request.PathValue("name")
+		if len(td.errList) == 0 {
+			var err error
+			td.result, err = receiver.RestoreBackup(ctx, request, name)
+			if err != nil {
+				td.errList = append(td.errList, err)
+				td.errStatusCode = http.StatusInternalServerError
+			}
+			td.result = td.result
+		}
+		buf := bytes.NewBuffer(nil)
+		if err := templates.ExecuteTemplate(buf, "POST /backups/{name}/restore RestoreBackup(ctx, request, name)", &td); err != nil {
+			slog.ErrorContext(request.Context(), "failed to render page", slog.String("path", request.URL.Path), slog.String("pattern", request.Pattern), slog.String("error", err.Error()))
+			http.Error(response, "failed to render page", http.StatusInternalServerError)
+			return
+		}
+		statusCode := cmp.Or(td.statusCode, td.errStatusCode, http.StatusOK)
+		if td.redirectURL != "" {
+			http.Redirect(response, request, td.redirectURL, statusCode)
+			return
+		}
+		if contentType := response.Header().Get("content-type"); contentType == "" {
+			response.Header().Set("content-type", "text/html; charset=utf-8")
+		}
+		response.Header().Set("content-length", strconv.Itoa(buf.Len()))
+		response.WriteHeader(statusCode)
+		_, _ = buf.WriteTo(response)
+	})
+	mux.HandleFunc("GET /compare", func(response http.ResponseWriter, request *http.Request) {
+		var td = templateData[routesReceiver, comparison]{receiver: receiver, response: response, request: request, pathsPrefix: pathsPrefix}
+		ctx := request.Context()
+		if len(td.errList) == 0 {
+			var err error
+			td.result, err = receiver.Compare(ctx)
+			if err != nil {
+				td.errList = append(td.errList, err)
+				td.errStatusCode = http.StatusInternalServerError
+			}
+			td.result = td.result
+		}
+		buf := bytes.NewBuffer(nil)
+		if err := templates.ExecuteTemplate(buf, "GET /compare Compare(ctx)", &td); err != nil {
 			slog.ErrorContext(request.Context(), "failed to render page", slog.String("path", request.URL.Path), slog.String("pattern", request.Pattern), slog.String("error", err.Error()))
 			http.Error(response, "failed to render page", http.StatusInternalServerError)
 			return
@@ -58,11 +299,11 @@ func routes(mux *http.ServeMux, receiver Provider) TemplateRoutePaths {
 		_, _ = buf.WriteTo(response)
 	})
 	mux.HandleFunc("POST /down", func(response http.ResponseWriter, request *http.Request) {
-		var td = templateData[Provider, *goose.MigrationResult]{receiver: receiver, response: response, request: request, pathsPrefix: pathsPrefix}
+		var td = templateData[routesReceiver, runResult]{receiver: receiver, response: response, request: request, pathsPrefix: pathsPrefix}
 		ctx := request.Context()
 		if len(td.errList) == 0 {
 			var err error
-			td.result, err = receiver.Down(ctx)
+			td.result, err = receiver.Down(ctx, request)
 			if err != nil {
 				td.errList = append(td.errList, err)
 				td.errStatusCode = http.StatusInternalServerError
@@ -70,7 +311,7 @@ func routes(mux *http.ServeMux, receiver Provider) TemplateRoutePaths {
 			td.result = td.result
 		}
 		buf := bytes.NewBuffer(nil)
-		if err := templates.ExecuteTemplate(buf, "POST /down Down(ctx)", &td); err != nil {
+		if err := templates.ExecuteTemplate(buf, "POST /down Down(ctx, request)", &td); err != nil {
 			slog.ErrorContext(request.Context(), "failed to render page", slog.String("path", request.URL.Path), slog.String("pattern", request.Pattern), slog.String("error", err.Error()))
 			http.Error(response, "failed to render page", http.StatusInternalServerError)
 			return
@@ -88,7 +329,7 @@ func routes(mux *http.ServeMux, receiver Provider) TemplateRoutePaths {
 		_, _ = buf.WriteTo(response)
 	})
 	mux.HandleFunc("POST /down-to/{version}", func(response http.ResponseWriter, request *http.Request) {
-		var td = templateData[Provider, []*goose.MigrationResult]{receiver: receiver, response: response, request: request, pathsPrefix: pathsPrefix}
+		var td = templateData[routesReceiver, runResult]{receiver: receiver, response: response, request: request, pathsPrefix: pathsPrefix}
 		ctx := request.Context()
 		versionParsed, err := strconv.ParseInt(request.PathValue("version"), 10, 64)
 		if err != nil {
@@ -98,7 +339,41 @@ func routes(mux *http.ServeMux, receiver Provider) TemplateRoutePaths {
 		version := versionParsed
 		if len(td.errList) == 0 {
 			var err error
-			td.result, err = receiver.DownTo(ctx, version)
+			td.result, err = receiver.DownTo(ctx, request, version)
+			if err != nil {
+				td.errList = append(td.errList, err)
+				td.errStatusCode = http.StatusInternalServerError
+			}
+			td.result = td.result
+		}
+		buf := bytes.NewBuffer(nil)
+		if err := templates.ExecuteTemplate(buf, "POST /down-to/{version} DownTo(ctx, request, version)", &td); err != nil {
+			slog.ErrorContext(request.Context(), "failed to render page", slog.String("path", request.URL.Path), slog.String("pattern", request.Pattern), slog.String("error", err.Error()))
+			http.Error(response, "failed to render page", http.StatusInternalServerError)
+			return
+		}
+		statusCode := cmp.Or(td.statusCode, td.errStatusCode, http.StatusOK)
+		if td.redirectURL != "" {
+			http.Redirect(response, request, td.redirectURL, statusCode)
+			return
+		}
+		if contentType := response.Header().Get("content-type"); contentType == "" {
+			response.Header().Set("content-type", "text/html; charset=utf-8")
+		}
+		response.Header().Set("content-length", strconv.Itoa(buf.Len()))
+		response.WriteHeader(statusCode)
+		_, _ = buf.WriteTo(response)
+	})
+	mux.HandleFunc("POST /migrations/new", func(response http.ResponseWriter, request *http.Request) {
+		var td = templateData[routesReceiver, newMigration]{receiver: receiver, response: response, request: request, pathsPrefix: pathsPrefix}
+		ctx := request.Context()
+		request.ParseForm()
+		var form newMigrationForm
+		form.Name = request.FormValue("name")
+		form.Type = request.FormValue("type")
+		if len(td.errList) == 0 {
+			var err error
+			td.result, err = receiver.NewMigration(ctx, form)
 			if err != nil {
 				td.errList = append(td.errList, err)
 				td.errStatusCode = http.StatusInternalServerError
@@ -106,7 +381,235 @@ func routes(mux *http.ServeMux, receiver Provider) TemplateRoutePaths {
 			td.result = td.result
 		}
 		buf := bytes.NewBuffer(nil)
-		if err := templates.ExecuteTemplate(buf, "POST /down-to/{version} DownTo(ctx, version)", &td); err != nil {
+		if err := templates.ExecuteTemplate(buf, "POST /migrations/new NewMigration(ctx, form)", &td); err != nil {
+			slog.ErrorContext(request.Context(), "failed to render page", slog.String("path", request.URL.Path), slog.String("pattern", request.Pattern), slog.String("error", err.Error()))
+			http.Error(response, "failed to render page", http.StatusInternalServerError)
+			return
+		}
+		statusCode := cmp.Or(td.statusCode, td.errStatusCode, http.StatusOK)
+		if td.redirectURL != "" {
+			http.Redirect(response, request, td.redirectURL, statusCode)
+			return
+		}
+		if contentType := response.Header().Get("content-type"); contentType == "" {
+			response.Header().Set("content-type", "text/html; charset=utf-8")
+		}
+		response.Header().Set("content-length", strconv.Itoa(buf.Len()))
+		response.WriteHeader(statusCode)
+		_, _ = buf.WriteTo(response)
+	})
+	mux.HandleFunc("GET /migrations/{version}", func(response http.ResponseWriter, request *http.Request) {
+		var td = templateData[routesReceiver, migrationDetail]{receiver: receiver, response: response, request: request, pathsPrefix: pathsPrefix}
+		ctx := request.Context()
+		versionParsed, err := strconv.ParseInt(request.PathValue("version"), 10, 64)
+		if err != nil {
+			td.errList = append(td.errList, err)
+			td.errStatusCode = http.StatusBadRequest
+		}
+		version := versionParsed
+		if len(td.errList) == 0 {
+			var err error
+			td.result, err = receiver.Migration(ctx, version)
+			if err != nil {
+				td.errList = append(td.errList, err)
+				td.errStatusCode = http.StatusInternalServerError
+			}
+			td.result = td.result
+		}
+		buf := bytes.NewBuffer(nil)
+		if err := templates.ExecuteTemplate(buf, "GET /migrations/{version} Migration(ctx, version)", &td); err != nil {
+			slog.ErrorContext(request.Context(), "failed to render page", slog.String("path", request.URL.Path), slog.String("pattern", request.Pattern), slog.String("error", err.Error()))
+			http.Error(response, "failed to render page", http.StatusInternalServerError)
+			return
+		}
+		statusCode := cmp.Or(td.statusCode, td.errStatusCode, http.StatusOK)
+		if td.redirectURL != "" {
+			http.Redirect(response, request, td.redirectURL, statusCode)
+			return
+		}
+		if contentType := response.Header().Get("content-type"); contentType == "" {
+			response.Header().Set("content-type", "text/html; charset=utf-8")
+		}
+		response.Header().Set("content-length", strconv.Itoa(buf.Len()))
+		response.WriteHeader(statusCode)
+		_, _ = buf.WriteTo(response)
+	})
+	mux.HandleFunc("POST /redo", func(response http.ResponseWriter, request *http.Request) {
+		var td = templateData[routesReceiver, runResult]{receiver: receiver, response: response, request: request, pathsPrefix: pathsPrefix}
+		ctx := request.Context()
+		if len(td.errList) == 0 {
+			var err error
+			td.result, err = receiver.Redo(ctx, request)
+			if err != nil {
+				td.errList = append(td.errList, err)
+				td.errStatusCode = http.StatusInternalServerError
+			}
+			td.result = td.result
+		}
+		buf := bytes.NewBuffer(nil)
+		if err := templates.ExecuteTemplate(buf, "POST /redo Redo(ctx, request)", &td); err != nil {
+			slog.ErrorContext(request.Context(), "failed to render page", slog.String("path", request.URL.Path), slog.String("pattern", request.Pattern), slog.String("error", err.Error()))
+			http.Error(response, "failed to render page", http.StatusInternalServerError)
+			return
+		}
+		statusCode := cmp.Or(td.statusCode, td.errStatusCode, http.StatusOK)
+		if td.redirectURL != "" {
+			http.Redirect(response, request, td.redirectURL, statusCode)
+			return
+		}
+		if contentType := response.Header().Get("content-type"); contentType == "" {
+			response.Header().Set("content-type", "text/html; charset=utf-8")
+		}
+		response.Header().Set("content-length", strconv.Itoa(buf.Len()))
+		response.WriteHeader(statusCode)
+		_, _ = buf.WriteTo(response)
+	})
+	mux.HandleFunc("POST /reset", func(response http.ResponseWriter, request *http.Request) {
+		var td = templateData[routesReceiver, runResult]{receiver: receiver, response: response, request: request, pathsPrefix: pathsPrefix}
+		ctx := request.Context()
+		if len(td.errList) == 0 {
+			var err error
+			td.result, err = receiver.Reset(ctx, request)
+			if err != nil {
+				td.errList = append(td.errList, err)
+				td.errStatusCode = http.StatusInternalServerError
+			}
+			td.result = td.result
+		}
+		buf := bytes.NewBuffer(nil)
+		if err := templates.ExecuteTemplate(buf, "POST /reset Reset(ctx, request)", &td); err != nil {
+			slog.ErrorContext(request.Context(), "failed to render page", slog.String("path", request.URL.Path), slog.String("pattern", request.Pattern), slog.String("error", err.Error()))
+			http.Error(response, "failed to render page", http.StatusInternalServerError)
+			return
+		}
+		statusCode := cmp.Or(td.statusCode, td.errStatusCode, http.StatusOK)
+		if td.redirectURL != "" {
+			http.Redirect(response, request, td.redirectURL, statusCode)
+			return
+		}
+		if contentType := response.Header().Get("content-type"); contentType == "" {
+			response.Header().Set("content-type", "text/html; charset=utf-8")
+		}
+		response.Header().Set("content-length", strconv.Itoa(buf.Len()))
+		response.WriteHeader(statusCode)
+		_, _ = buf.WriteTo(response)
+	})
+	mux.HandleFunc("GET /schedules", func(response http.ResponseWriter, request *http.Request) {
+		var td = templateData[routesReceiver, schedulesPage]{receiver: receiver, response: response, request: request, pathsPrefix: pathsPrefix}
+		ctx := request.Context()
+		if len(td.errList) == 0 {
+			var err error
+			td.result, err = receiver.Schedules(ctx)
+			if err != nil {
+				td.errList = append(td.errList, err)
+				td.errStatusCode = http.StatusInternalServerError
+			}
+			td.result = td.result
+		}
+		buf := bytes.NewBuffer(nil)
+		if err := templates.ExecuteTemplate(buf, "GET /schedules Schedules(ctx)", &td); err != nil {
+			slog.ErrorContext(request.Context(), "failed to render page", slog.String("path", request.URL.Path), slog.String("pattern", request.Pattern), slog.String("error", err.Error()))
+			http.Error(response, "failed to render page", http.StatusInternalServerError)
+			return
+		}
+		statusCode := cmp.Or(td.statusCode, td.errStatusCode, http.StatusOK)
+		if td.redirectURL != "" {
+			http.Redirect(response, request, td.redirectURL, statusCode)
+			return
+		}
+		if contentType := response.Header().Get("content-type"); contentType == "" {
+			response.Header().Set("content-type", "text/html; charset=utf-8")
+		}
+		response.Header().Set("content-length", strconv.Itoa(buf.Len()))
+		response.WriteHeader(statusCode)
+		_, _ = buf.WriteTo(response)
+	})
+	mux.HandleFunc("POST /schedules", func(response http.ResponseWriter, request *http.Request) {
+		var td = templateData[routesReceiver, schedulesPage]{receiver: receiver, response: response, request: request, pathsPrefix: pathsPrefix}
+		ctx := request.Context()
+		request.ParseForm()
+		var form scheduleForm
+		{
+			value, err := strconv.ParseInt(request.FormValue("version"), 10, 64)
+			if err != nil {
+				td.errList = append(td.errList, err)
+				td.errStatusCode = http.StatusBadRequest
+			}
+			form.Version = value
+		}
+		form.At = request.FormValue("at")
+		if len(td.errList) == 0 {
+			var err error
+			td.result, err = receiver.CreateSchedule(ctx, request, form)
+			if err != nil {
+				td.errList = append(td.errList, err)
+				td.errStatusCode = http.StatusInternalServerError
+			}
+			td.result = td.result
+		}
+		buf := bytes.NewBuffer(nil)
+		if err := templates.ExecuteTemplate(buf, "POST /schedules CreateSchedule(ctx, request, form)", &td); err != nil {
+			slog.ErrorContext(request.Context(), "failed to render page", slog.String("path", request.URL.Path), slog.String("pattern", request.Pattern), slog.String("error", err.Error()))
+			http.Error(response, "failed to render page", http.StatusInternalServerError)
+			return
+		}
+		statusCode := cmp.Or(td.statusCode, td.errStatusCode, http.StatusOK)
+		if td.redirectURL != "" {
+			http.Redirect(response, request, td.redirectURL, statusCode)
+			return
+		}
+		if contentType := response.Header().Get("content-type"); contentType == "" {
+			response.Header().Set("content-type", "text/html; charset=utf-8")
+		}
+		response.Header().Set("content-length", strconv.Itoa(buf.Len()))
+		response.WriteHeader(statusCode)
+		_, _ = buf.WriteTo(response)
+	})
+	mux.HandleFunc("POST /schedules/{id}/cancel", func(response http.ResponseWriter, request *http.Request) {
+		var td = templateData[routesReceiver, schedulesPage]{receiver: receiver, response: response, request: request, pathsPrefix: pathsPrefix}
+		ctx := request.Context()
+		id := request.PathValue("id")
+		if len(td.errList) == 0 {
+			var err error
+			td.result, err = receiver.CancelSchedule(ctx, id)
+			if err != nil {
+				td.errList = append(td.errList, err)
+				td.errStatusCode = http.StatusInternalServerError
+			}
+			td.result = td.result
+		}
+		buf := bytes.NewBuffer(nil)
+		if err := templates.ExecuteTemplate(buf, "POST /schedules/{id}/cancel CancelSchedule(ctx, id)", &td); err != nil {
+			slog.ErrorContext(request.Context(), "failed to render page", slog.String("path", request.URL.Path), slog.String("pattern", request.Pattern), slog.String("error", err.Error()))
+			http.Error(response, "failed to render page", http.StatusInternalServerError)
+			return
+		}
+		statusCode := cmp.Or(td.statusCode, td.errStatusCode, http.StatusOK)
+		if td.redirectURL != "" {
+			http.Redirect(response, request, td.redirectURL, statusCode)
+			return
+		}
+		if contentType := response.Header().Get("content-type"); contentType == "" {
+			response.Header().Set("content-type", "text/html; charset=utf-8")
+		}
+		response.Header().Set("content-length", strconv.Itoa(buf.Len()))
+		response.WriteHeader(statusCode)
+		_, _ = buf.WriteTo(response)
+	})
+	mux.HandleFunc("GET /schema", func(response http.ResponseWriter, request *http.Request) {
+		var td = templateData[routesReceiver, Schema]{receiver: receiver, response: response, request: request, pathsPrefix: pathsPrefix}
+		ctx := request.Context()
+		if len(td.errList) == 0 {
+			var err error
+			td.result, err = receiver.Schema(ctx)
+			if err != nil {
+				td.errList = append(td.errList, err)
+				td.errStatusCode = http.StatusInternalServerError
+			}
+			td.result = td.result
+		}
+		buf := bytes.NewBuffer(nil)
+		if err := templates.ExecuteTemplate(buf, "GET /schema Schema(ctx)", &td); err != nil {
 			slog.ErrorContext(request.Context(), "failed to render page", slog.String("path", request.URL.Path), slog.String("pattern", request.Pattern), slog.String("error", err.Error()))
 			http.Error(response, "failed to render page", http.StatusInternalServerError)
 			return
@@ -124,11 +627,11 @@ func routes(mux *http.ServeMux, receiver Provider) TemplateRoutePaths {
 		_, _ = buf.WriteTo(response)
 	})
 	mux.HandleFunc("POST /up", func(response http.ResponseWriter, request *http.Request) {
-		var td = templateData[Provider, []*goose.MigrationResult]{receiver: receiver, response: response, request: request, pathsPrefix: pathsPrefix}
+		var td = templateData[routesReceiver, runResult]{receiver: receiver, response: response, request: request, pathsPrefix: pathsPrefix}
 		ctx := request.Context()
 		if len(td.errList) == 0 {
 			var err error
-			td.result, err = receiver.Up(ctx)
+			td.result, err = receiver.Up(ctx, request)
 			if err != nil {
 				td.errList = append(td.errList, err)
 				td.errStatusCode = http.StatusInternalServerError
@@ -136,7 +639,7 @@ func routes(mux *http.ServeMux, receiver Provider) TemplateRoutePaths {
 			td.result = td.result
 		}
 		buf := bytes.NewBuffer(nil)
-		if err := templates.ExecuteTemplate(buf, "POST /up Up(ctx)", &td); err != nil {
+		if err := templates.ExecuteTemplate(buf, "POST /up Up(ctx, request)", &td); err != nil {
 			slog.ErrorContext(request.Context(), "failed to render page", slog.String("path", request.URL.Path), slog.String("pattern", request.Pattern), slog.String("error", err.Error()))
 			http.Error(response, "failed to render page", http.StatusInternalServerError)
 			return
@@ -154,7 +657,7 @@ func routes(mux *http.ServeMux, receiver Provider) TemplateRoutePaths {
 		_, _ = buf.WriteTo(response)
 	})
 	mux.HandleFunc("POST /up-to/{version}", func(response http.ResponseWriter, request *http.Request) {
-		var td = templateData[Provider, []*goose.MigrationResult]{receiver: receiver, response: response, request: request, pathsPrefix: pathsPrefix}
+		var td = templateData[routesReceiver, runResult]{receiver: receiver, response: response, request: request, pathsPrefix: pathsPrefix}
 		ctx := request.Context()
 		versionParsed, err := strconv.ParseInt(request.PathValue("version"), 10, 64)
 		if err != nil {
@@ -164,7 +667,7 @@ func routes(mux *http.ServeMux, receiver Provider) TemplateRoutePaths {
 		version := versionParsed
 		if len(td.errList) == 0 {
 			var err error
-			td.result, err = receiver.UpTo(ctx, version)
+			td.result, err = receiver.UpTo(ctx, request, version)
 			if err != nil {
 				td.errList = append(td.errList, err)
 				td.errStatusCode = http.StatusInternalServerError
@@ -172,7 +675,7 @@ func routes(mux *http.ServeMux, receiver Provider) TemplateRoutePaths {
 			td.result = td.result
 		}
 		buf := bytes.NewBuffer(nil)
-		if err := templates.ExecuteTemplate(buf, "POST /up-to/{version} UpTo(ctx, version)", &td); err != nil {
+		if err := templates.ExecuteTemplate(buf, "POST /up-to/{version} UpTo(ctx, request, version)", &td); err != nil {
 			slog.ErrorContext(request.Context(), "failed to render page", slog.String("path", request.URL.Path), slog.String("pattern", request.Pattern), slog.String("error", err.Error()))
 			http.Error(response, "failed to render page", http.StatusInternalServerError)
 			return
@@ -264,6 +767,34 @@ func (routePaths TemplateRoutePaths) Status() string {
 	return "/"
 }
 
+func (routePaths TemplateRoutePaths) ApplyMissing(version int64) string {
+	return path.Join(cmp.Or(routePaths.pathsPrefix, "/"), "apply", strconv.FormatInt(int64(version), 10))
+}
+
+func (routePaths TemplateRoutePaths) Approvals() string {
+	return path.Join(cmp.Or(routePaths.pathsPrefix, "/"), "approvals")
+}
+
+func (routePaths TemplateRoutePaths) Approve(id string) string {
+	return path.Join(cmp.Or(routePaths.pathsPrefix, "/"), "approvals", id, "approve")
+}
+
+func (routePaths TemplateRoutePaths) Reject(id string) string {
+	return path.Join(cmp.Or(routePaths.pathsPrefix, "/"), "approvals", id, "reject")
+}
+
+func (routePaths TemplateRoutePaths) Backups() string {
+	return path.Join(cmp.Or(routePaths.pathsPrefix, "/"), "backups")
+}
+
+func (routePaths TemplateRoutePaths) RestoreBackup(name string) string {
+	return path.Join(cmp.Or(routePaths.pathsPrefix, "/"), "backups", name, "restore")
+}
+
+func (routePaths TemplateRoutePaths) Compare() string {
+	return path.Join(cmp.Or(routePaths.pathsPrefix, "/"), "compare")
+}
+
 func (routePaths TemplateRoutePaths) Down() string {
 	return path.Join(cmp.Or(routePaths.pathsPrefix, "/"), "down")
 }
@@ -272,6 +803,38 @@ func (routePaths TemplateRoutePaths) DownTo(version int64) string {
 	return path.Join(cmp.Or(routePaths.pathsPrefix, "/"), "down-to", strconv.FormatInt(int64(version), 10))
 }
 
+func (routePaths TemplateRoutePaths) NewMigration() string {
+	return path.Join(cmp.Or(routePaths.pathsPrefix, "/"), "migrations/new")
+}
+
+func (routePaths TemplateRoutePaths) Migration(version int64) string {
+	return path.Join(cmp.Or(routePaths.pathsPrefix, "/"), "migrations", strconv.FormatInt(int64(version), 10))
+}
+
+func (routePaths TemplateRoutePaths) Redo() string {
+	return path.Join(cmp.Or(routePaths.pathsPrefix, "/"), "redo")
+}
+
+func (routePaths TemplateRoutePaths) Reset() string {
+	return path.Join(cmp.Or(routePaths.pathsPrefix, "/"), "reset")
+}
+
+func (routePaths TemplateRoutePaths) Schedules() string {
+	return path.Join(cmp.Or(routePaths.pathsPrefix, "/"), "schedules")
+}
+
+func (routePaths TemplateRoutePaths) CreateSchedule() string {
+	return path.Join(cmp.Or(routePaths.pathsPrefix, "/"), "schedules")
+}
+
+func (routePaths TemplateRoutePaths) CancelSchedule(id string) string {
+	return path.Join(cmp.Or(routePaths.pathsPrefix, "/"), "schedules", id, "cancel")
+}
+
+func (routePaths TemplateRoutePaths) Schema() string {
+	return path.Join(cmp.Or(routePaths.pathsPrefix, "/"), "schema")
+}
+
 func (routePaths TemplateRoutePaths) Up() string {
 	return path.Join(cmp.Or(routePaths.pathsPrefix, "/"), "up")
 }
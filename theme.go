@@ -0,0 +1,95 @@
+package gooseglass
+
+import (
+	"fmt"
+	"html/template"
+	"io/fs"
+	"maps"
+	"strings"
+)
+
+// baseTemplates is the unexecuted set parsed from provider.gohtml. Pages clones it for overrides,
+// because html/template can not add templates to a set after it has executed.
+var baseTemplates = template.Must(templates.Clone())
+
+// WithTemplates overrides named templates from provider.gohtml with the ones defined in
+// overrides, for example "head" to add a stylesheet or "status page" to wrap the status table in
+// another layout. Templates overrides defines that are not in provider.gohtml are added, so an
+// override can call its own partials. Route templates, those named with an HTTP route such as
+// "GET /schema Schema(ctx)", are ignored so the pages keep their handlers.
+//
+// Overrides are executed with the same data as the templates they replace. Page templates get a
+// templateData whose methods are stable:
+//
+//   - .Result is the value the route method returned and .Err the error, if any.
+//   - .Request is the *http.Request and .Path builds links to every page, for example
+//     .Path.Schema or .Path.Migration 3.
+//   - .StatusCode, .Header, .Redirect, .TriggerRefreshMigrations and .StatusCodeFromError set
+//     the response status and headers.
+//   - .WindowBlocked and .MigrationFailure unwrap .Err for the "migrate error" template.
+//   - .MuxtVersion is the version of the code generator.
+//
+// Partials such as "migrate result" get the value passed by the template that calls them; each
+// template in provider.gohtml names that type in a gotype comment.
+//
+// Overrides only apply to the pages registered by the Pages call they are given to and must not
+// have been executed. Pass the functions they call to WithTemplateFuncs as well.
+func WithTemplates(overrides *template.Template) Option {
+	return func(s *server) {
+		s.overrides = append(s.overrides, func(template.FuncMap) (*template.Template, error) { return overrides, nil })
+	}
+}
+
+// WithTemplatesFS parses the files in fsys matching patterns with the WithTemplateFuncs functions
+// and uses their defines as in WithTemplates. Pages panics if they do not parse.
+func WithTemplatesFS(fsys fs.FS, patterns ...string) Option {
+	return func(s *server) {
+		s.overrides = append(s.overrides, func(funcs template.FuncMap) (*template.Template, error) {
+			return template.New("").Funcs(funcs).ParseFS(fsys, patterns...)
+		})
+	}
+}
+
+// WithTemplateFuncs adds functions the template overrides call.
+func WithTemplateFuncs(funcs template.FuncMap) Option {
+	return func(s *server) {
+		if s.templateFuncs == nil {
+			s.templateFuncs = make(template.FuncMap)
+		}
+		maps.Copy(s.templateFuncs, funcs)
+	}
+}
+
+// templateSet returns the templates the routes of this Pages call render: the package templates,
+// or a copy of them with the overrides applied.
+func (s *server) templateSet() (*template.Template, error) {
+	if len(s.overrides) == 0 && len(s.templateFuncs) == 0 {
+		return templates, nil
+	}
+	set, err := baseTemplates.Clone()
+	if err != nil {
+		return nil, err
+	}
+	set.Funcs(s.templateFuncs)
+	for _, parse := range s.overrides {
+		o, err := parse(s.templateFuncs)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range o.Templates() {
+			if t.Tree == nil || t.Tree.Root == nil || isRouteTemplate(t.Name()) {
+				continue
+			}
+			if _, err := set.AddParseTree(t.Name(), t.Tree); err != nil {
+				return nil, fmt.Errorf("overriding template %q: %w", t.Name(), err)
+			}
+		}
+	}
+	return set, nil
+}
+
+// isRouteTemplate reports whether muxt generates a handler for the template name.
+func isRouteTemplate(name string) bool {
+	method, path, _ := strings.Cut(name, " ")
+	return strings.HasPrefix(name, "/") || method == strings.ToUpper(method) && strings.HasPrefix(path, "/")
+}
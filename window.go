@@ -0,0 +1,229 @@
+package gooseglass
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaintenanceWindow opens at every minute matching a cron expression in its location and stays open
+// for its duration.
+type MaintenanceWindow struct {
+	expression string
+	duration   time.Duration
+	location   *time.Location
+
+	minute, hour, dom, month, dow uint64
+	anyDOM, anyDOW                bool
+}
+
+// ParseMaintenanceWindow parses a five field cron expression (minute, hour, day of month, month,
+// day of week) supporting "*", lists, ranges and steps. A nil location means UTC.
+func ParseMaintenanceWindow(expression string, duration time.Duration, location *time.Location) (MaintenanceWindow, error) {
+	if duration <= 0 {
+		return MaintenanceWindow{}, errors.New("maintenance window duration must be positive")
+	}
+	if location == nil {
+		location = time.UTC
+	}
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return MaintenanceWindow{}, fmt.Errorf("cron expression %q must have 5 fields", expression)
+	}
+	w := MaintenanceWindow{expression: expression, duration: duration, location: location}
+	for i, field := range []struct {
+		bits     *uint64
+		min, max int
+	}{
+		{&w.minute, 0, 59},
+		{&w.hour, 0, 23},
+		{&w.dom, 1, 31},
+		{&w.month, 1, 12},
+		{&w.dow, 0, 7},
+	} {
+		bits, err := parseCronField(fields[i], field.min, field.max)
+		if err != nil {
+			return MaintenanceWindow{}, fmt.Errorf("cron expression %q: %w", expression, err)
+		}
+		*field.bits = bits
+	}
+	if w.dow&(1<<7) != 0 {
+		w.dow |= 1 // 7 is also Sunday
+	}
+	w.anyDOM, w.anyDOW = fields[2] == "*", fields[4] == "*"
+	return w, nil
+}
+
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for part := range strings.SplitSeq(field, ",") {
+		span, stepText, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepText)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", part)
+			}
+			step = n
+		}
+		lo, hi := min, max
+		if span != "*" {
+			first, last, isRange := strings.Cut(span, "-")
+			n, err := strconv.Atoi(first)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+			lo, hi = n, n
+			if isRange {
+				if hi, err = strconv.Atoi(last); err != nil {
+					return 0, fmt.Errorf("invalid range %q", part)
+				}
+			} else if hasStep {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q is out of range %d-%d", part, min, max)
+		}
+		for n := lo; n <= hi; n += step {
+			bits |= 1 << n
+		}
+	}
+	return bits, nil
+}
+
+func (w MaintenanceWindow) String() string {
+	return fmt.Sprintf("%s for %s (%s)", w.expression, w.duration, w.location)
+}
+
+func (w MaintenanceWindow) matchesDay(t time.Time) bool {
+	dom := w.dom&(1<<t.Day()) != 0
+	dow := w.dow&(1<<int(t.Weekday())) != 0
+	if w.month&(1<<int(t.Month())) == 0 {
+		return false
+	}
+	switch {
+	case w.anyDOM:
+		return dow
+	case w.anyDOW:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+func (w MaintenanceWindow) matches(t time.Time) bool {
+	return w.matchesDay(t) && w.hour&(1<<t.Hour()) != 0 && w.minute&(1<<t.Minute()) != 0
+}
+
+// IsOpen reports whether t is within duration of a start time.
+func (w MaintenanceWindow) IsOpen(t time.Time) bool {
+	t = t.In(w.location).Truncate(time.Minute)
+	for start := t; t.Sub(start) < w.duration; start = start.Add(-time.Minute) {
+		if w.matches(start) {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns the first start time after t within a year.
+func (w MaintenanceWindow) Next(t time.Time) (time.Time, bool) {
+	t = t.In(w.location).Truncate(time.Minute).Add(time.Minute)
+	end := t.AddDate(1, 0, 1)
+	for t.Before(end) {
+		if !w.matchesDay(t) {
+			y, m, d := t.Date()
+			t = time.Date(y, m, d+1, 0, 0, 0, 0, w.location)
+			continue
+		}
+		if w.matches(t) {
+			return t, true
+		}
+		t = t.Add(time.Minute)
+	}
+	return time.Time{}, false
+}
+
+// windowState is the maintenance window summary on the status page.
+type windowState struct {
+	Open          bool
+	Next          time.Time
+	EmergencyOpen bool
+	HasEmergency  bool
+}
+
+func newWindowState(windows, emergency []MaintenanceWindow, now time.Time) *windowState {
+	if len(windows) == 0 && len(emergency) == 0 {
+		return nil
+	}
+	state := &windowState{
+		Open:          len(windows) == 0 || anyOpen(windows, now),
+		EmergencyOpen: anyOpen(emergency, now),
+		HasEmergency:  len(emergency) > 0,
+	}
+	for _, w := range windows {
+		if next, ok := w.Next(now); ok && (state.Next.IsZero() || next.Before(state.Next)) {
+			state.Next = next
+		}
+	}
+	return state
+}
+
+func anyOpen(windows []MaintenanceWindow, now time.Time) bool {
+	for _, w := range windows {
+		if w.IsOpen(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// windowError is returned when a maintenance window policy blocks a run.
+type windowError struct {
+	Plan     Plan
+	Next     time.Time
+	Override bool
+}
+
+func (e *windowError) Error() string {
+	msg := fmt.Sprintf("%s is blocked outside the maintenance windows", e.Plan)
+	if e.Override {
+		msg = fmt.Sprintf("%s is blocked outside the emergency windows; enter an override reason to roll back anyway", e.Plan)
+	}
+	if !e.Next.IsZero() {
+		msg += fmt.Sprintf("; the next window opens %s", e.Next.Format(time.RFC3339))
+	}
+	return msg
+}
+
+func (e *windowError) StatusCode() int { return http.StatusLocked }
+
+// checkWindows returns a windowError if the plan may not run now. Rolling back outside the
+// emergency windows is allowed with an override reason. Plans that roll back and apply again, like
+// redo, need both windows.
+func (s *server) checkWindows(plan Plan, now time.Time) error {
+	if plan.rollsBack() && len(s.emergencyWindows) > 0 && plan.Reason == "" && !anyOpen(s.emergencyWindows, now) {
+		return &windowError{Plan: plan, Override: true}
+	}
+	if plan.applies() && len(s.windows) > 0 && !anyOpen(s.windows, now) {
+		state := newWindowState(s.windows, nil, now)
+		return &windowError{Plan: plan, Next: state.Next}
+	}
+	return nil
+}
+
+// WithMaintenanceWindows only allows Up, UpTo, applying missing migrations, Redo and reset-up while
+// one of the windows is open.
+func WithMaintenanceWindows(windows ...MaintenanceWindow) Option {
+	return func(s *server) { s.windows = append(s.windows, windows...) }
+}
+
+// WithEmergencyWindows only allows Down, DownTo and Redo while one of the windows is open unless the
+// person rolling back enters an override reason.
+func WithEmergencyWindows(windows ...MaintenanceWindow) Option {
+	return func(s *server) { s.emergencyWindows = append(s.emergencyWindows, windows...) }
+}
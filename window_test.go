@@ -0,0 +1,56 @@
+package gooseglass_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/crhntr/gooseglass"
+)
+
+func TestMaintenanceWindow(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	// Weeknights from 22:00 to 23:30 New York time.
+	window, err := gooseglass.ParseMaintenanceWindow("0 22 * * 1-5", 90*time.Minute, newYork)
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		name string
+		at   time.Time
+		open bool
+	}{
+		{"at opening", time.Date(2026, 10, 19, 22, 0, 0, 0, newYork), true},
+		{"before closing", time.Date(2026, 10, 19, 23, 29, 59, 0, newYork), true},
+		{"at closing", time.Date(2026, 10, 19, 23, 30, 0, 0, newYork), false},
+		{"past midnight friday", time.Date(2026, 10, 24, 0, 10, 0, 0, newYork), false},
+		{"saturday", time.Date(2026, 10, 24, 22, 30, 0, 0, newYork), false},
+		{"in UTC", time.Date(2026, 10, 20, 2, 15, 0, 0, time.UTC), true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.open, window.IsOpen(tt.at))
+		})
+	}
+
+	next, ok := window.Next(time.Date(2026, 10, 23, 23, 0, 0, 0, newYork))
+	require.True(t, ok)
+	assert.True(t, next.Equal(time.Date(2026, 10, 26, 22, 0, 0, 0, newYork)), next)
+
+	_, ok = must(gooseglass.ParseMaintenanceWindow("0 0 31 2 *", time.Hour, nil)).Next(time.Now())
+	assert.False(t, ok)
+
+	for _, expression := range []string{"* * * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *", "x * * * *"} {
+		_, err := gooseglass.ParseMaintenanceWindow(expression, time.Hour, nil)
+		assert.Error(t, err, expression)
+	}
+}
+
+func must[T any](v T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}